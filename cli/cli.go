@@ -0,0 +1,272 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"passwdgen/config"
+	"passwdgen/gen"
+	"passwdgen/hasher"
+	"passwdgen/i18n"
+	"passwdgen/phonetic"
+	"passwdgen/profile"
+	"strings"
+)
+
+const Name = "passwdgen"
+
+// DefaultLanguage 命令行的默认语言
+const DefaultLanguage = "en"
+
+// command 子命令
+type command func(args []string, out io.Writer) error
+
+var commands = map[string]command{}
+
+// Run 执行命令行,返回进程退出码
+func Run(args []string) int {
+	if err := run(args, os.Stdout); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		fmt.Fprintf(os.Stderr, "%s: %s\n", Name, i18n.LocalizeError(errorLanguage(), err))
+		return 1
+	}
+	return 0
+}
+
+// errorLanguage 错误提示使用用户设置的语言,未设置或配置无效时使用默认语言
+func errorLanguage() string {
+	if cfg, err := config.Load(); err == nil {
+		return configuredLanguage(cfg)
+	}
+	return DefaultLanguage
+}
+
+// configuredLanguage 返回用户在配置文件、环境变量或命名配置中设置的语言。
+// 配置的缺省语言是界面的默认语言,命令行未设置语言时使用 DefaultLanguage
+func configuredLanguage(cfg *config.Config) string {
+	if cfg.Language != "" && cfg.Origin("language").Source != config.SourceDefault {
+		return cfg.Language
+	}
+	return DefaultLanguage
+}
+
+func run(args []string, out io.Writer) error {
+	if len(args) > 0 {
+		if cmd, ok := commands[args[0]]; ok {
+			return cmd(args[1:], out)
+		}
+	}
+	return runGenerate(args, out)
+}
+
+// configFlags 覆盖配置项的命令行参数
+type configFlags struct {
+	fs      *flag.FlagSet
+	profile *string
+	// resolve 读取的命名配置,未指定时为nil
+	selected *profile.Profile
+	// 参数名到配置项的映射
+	keys map[string]string
+}
+
+func newConfigFlags(fs *flag.FlagSet) *configFlags {
+	defaultConf := gen.NewDefaultPasswdGenConf()
+	cf := &configFlags{fs: fs, keys: make(map[string]string)}
+	cf.profile = fs.String("profile", "", "use the named profile")
+	fs.Uint("length", uint(defaultConf.Length), "password length")
+	fs.Bool("number", defaultConf.EnableNumber, "use numbers")
+	fs.Bool("lowercase", defaultConf.EnableLowercase, "use lowercase letters")
+	fs.Bool("uppercase", defaultConf.EnableUppercase, "use uppercase letters")
+	fs.Bool("duplicate", defaultConf.EnableDuplicate, "allow duplicate characters")
+	fs.String("include", defaultConf.IncludeSpecialCharSet, "special characters to include")
+	fs.String("exclude", defaultConf.ExcludeSpecialCharSet, "characters to exclude")
+	fs.String("classes", "", "comma separated built-in character classes: "+strings.Join(gen.NamedCharClasses, ", "))
+	fs.String("format", config.OutputFormatPlain, "output format: plain or json")
+	for name, key := range map[string]string{
+		"length":    "generation.length",
+		"number":    "generation.number",
+		"lowercase": "generation.lowercase",
+		"uppercase": "generation.uppercase",
+		"duplicate": "generation.duplicate",
+		"include":   "generation.include",
+		"exclude":   "generation.exclude",
+		"classes":   "generation.classes",
+		"format":    "output.format",
+	} {
+		cf.keys[name] = key
+	}
+	return cf
+}
+
+// resolve 按 默认值 < 配置文件 < 环境变量 < 命名配置 < 命令行参数 的顺序合并配置
+func (cf *configFlags) resolve() (*config.Config, error) {
+	var layers []config.Layer
+	if *cf.profile != "" {
+		store, err := profile.OpenDefault()
+		if err != nil {
+			return nil, err
+		}
+		p, err := store.Get(*cf.profile)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", *cf.profile, err)
+		}
+		cf.selected = p
+		layers = append(layers, config.ProfileLayer(p.Name, p.Conf))
+	}
+	values := make(map[string]string)
+	cf.fs.Visit(func(f *flag.Flag) {
+		if key, ok := cf.keys[f.Name]; ok {
+			values[key] = f.Value.String()
+		}
+	})
+	layers = append(layers, config.FlagLayer(values))
+	return config.Load(layers...)
+}
+
+// runGenerate 生成密码
+func runGenerate(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(Name, flag.ContinueOnError)
+	cf := newConfigFlags(fs)
+	hf := newHashFlags(fs, cf)
+	spell := fs.Bool("phonetic", false, "print the phonetic spelling of each character")
+	chunk := fs.Int("chunk", 0, "group the plain text password into chunks of this many characters (0 = off)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unknown command %q", fs.Arg(0))
+	}
+	cfg, err := cf.resolve()
+	if err != nil {
+		return err
+	}
+	result, err := generate(cfg, cf.selected)
+	if err != nil {
+		return err
+	}
+	extras := &resultExtras{chunkSize: *chunk}
+	if extras.hashes, err = hf.hashes(result.Password, cfg.Hash); err != nil {
+		return err
+	}
+	if *spell {
+		extras.spelling = spellPassword(configuredLanguage(cfg), result.Password)
+	}
+	return writeResult(out, cfg.OutputFormat, configuredLanguage(cfg), result, extras)
+}
+
+// generate 按命名配置的生成方式生成密码,未使用命名配置时生成随机密码
+func generate(cfg *config.Config, p *profile.Profile) (*gen.PasswdGenResult, error) {
+	if p == nil {
+		return gen.GeneratePassword(cfg.Gen)
+	}
+	if p.Mode == profile.ModePassphrase {
+		conf := gen.NewDefaultPassphraseConf()
+		if p.Passphrase != nil {
+			c := *p.Passphrase
+			conf = &c
+		}
+		if conf.WordList == "" {
+			conf.WordList = languageWordList(cfg.Language)
+		}
+		return gen.GeneratePassphrase(conf)
+	}
+	return gen.GeneratePasswordWithPolicy(cfg.Gen, p.Policy)
+}
+
+// passwdOutput 命令行的JSON输出
+type passwdOutput struct {
+	Password     string  `json:"password"`
+	Strength     float64 `json:"strength"`
+	StrengthInfo string  `json:"strength_info"`
+	CostInfo     string  `json:"cost_info"`
+	// 哈希格式到哈希值的映射
+	Hashes map[hasher.Format]string `json:"hashes,omitempty"`
+	// 逐字符的读音拼写
+	Phonetic []*phoneticOutput `json:"phonetic,omitempty"`
+}
+
+type phoneticOutput struct {
+	Char     string `json:"char"`
+	Spelling string `json:"spelling"`
+}
+
+// resultExtras 密码之外的可选输出
+type resultExtras struct {
+	hashes   []*hasher.Result
+	spelling []phonetic.Token
+	// 纯文本输出时密码的分组大小,0表示不分组
+	chunkSize int
+}
+
+// writeResult 按输出格式写出结果,JSON 中的文本使用 lang 语言
+func writeResult(out io.Writer, format, lang string, result *gen.PasswdGenResult, extras *resultExtras) error {
+	if extras == nil {
+		extras = &resultExtras{}
+	}
+	hashes, spelling := extras.hashes, extras.spelling
+	if format == config.OutputFormatJSON {
+		// 强度文本与读音拼写使用同一语言
+		info, cost := strengthTexts(lang, result.Strength)
+		po := &passwdOutput{
+			Password:     result.Password,
+			Strength:     result.StrengthInt,
+			StrengthInfo: info,
+			CostInfo:     cost,
+		}
+		if len(hashes) > 0 {
+			po.Hashes = make(map[hasher.Format]string, len(hashes))
+			for _, h := range hashes {
+				po.Hashes[h.Format] = h.Hash
+			}
+		}
+		for _, token := range spelling {
+			po.Phonetic = append(po.Phonetic, &phoneticOutput{Char: token.Char, Spelling: token.Spelling})
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(po)
+	}
+	if _, err := fmt.Fprintln(out, gen.FormatChunked(result.Password, extras.chunkSize)); err != nil {
+		return err
+	}
+	// 哈希按 格式<TAB>哈希 逐行输出
+	for _, h := range hashes {
+		if _, err := fmt.Fprintf(out, "%s\t%s\n", h.Format, h.Hash); err != nil {
+			return err
+		}
+	}
+	if len(spelling) > 0 {
+		if _, err := fmt.Fprintln(out, phonetic.Format(spelling)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// strengthTexts 返回强度等级和破解时间的文本
+func strengthTexts(lang string, strength gen.Strength) (string, string) {
+	info, _ := i18n.Localize(lang, i18n.StrengthMessageId(strength))
+	var cost string
+	if messageId, ok := i18n.StrengthCostMessageId(strength); ok {
+		cost, _ = i18n.Localize(lang, messageId)
+	}
+	return info, cost
+}
+
+// spellPassword 按配置的语言返回读音拼写,缺省为英文
+func spellPassword(lang, passwd string) []phonetic.Token {
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	return phonetic.Spell(passwd, func(messageId i18n.MessageId) string {
+		value, err := i18n.Localize(lang, messageId)
+		if err != nil {
+			value, _ = i18n.Localize(DefaultLanguage, messageId)
+		}
+		return value
+	})
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"passwdgen/config"
+	"text/tabwriter"
+)
+
+func init() {
+	commands["config"] = runConfig
+}
+
+// runConfig passwdgen config show|validate
+func runConfig(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: %s config show|validate [flags]", Name)
+	}
+	sub := args[0]
+	fs := flag.NewFlagSet(Name+" config "+sub, flag.ContinueOnError)
+	cf := newConfigFlags(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	switch sub {
+	case "show":
+		cfg, err := cf.resolve()
+		if cfg != nil {
+			if werr := writeConfig(out, cfg); werr != nil {
+				return werr
+			}
+		}
+		return err
+	case "validate":
+		if _, err := cf.resolve(); err != nil {
+			return err
+		}
+		path, err := config.DefaultPath()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(out, "configuration OK (%s)\n", path)
+		return err
+	default:
+		return fmt.Errorf("unknown config command %q", sub)
+	}
+}
+
+func writeConfig(out io.Writer, cfg *config.Config) error {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+	for _, key := range config.Keys() {
+		fmt.Fprintf(tw, "%s\t%q\t%s\n", key, cfg.Value(key), cfg.Origin(key))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"golang.org/x/term"
+	"io"
+	"os"
+	"passwdgen/gen"
+	"strings"
+)
+
+func init() {
+	commands["derive"] = runDerive
+}
+
+// runDerive passwdgen derive,由主密码确定性派生站点密码
+func runDerive(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(Name+" derive", flag.ContinueOnError)
+	cf := newConfigFlags(fs)
+	site := fs.String("site", "", "site name, e.g. example.com (required)")
+	login := fs.String("login", "", "login name")
+	counter := fs.Uint("counter", 1, "counter, increase to rotate the password")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if strings.TrimSpace(*site) == "" {
+		return errors.New("site is required")
+	}
+	if *counter > 1<<32-1 {
+		return fmt.Errorf("counter %d out of range", *counter)
+	}
+	cfg, err := cf.resolve()
+	if err != nil {
+		return err
+	}
+	master, err := readSecret(os.Stdin, os.Stderr, "Master password")
+	if err != nil {
+		return err
+	}
+	result, err := gen.DerivePassword(&gen.DeriveConf{
+		Master:  master,
+		Site:    *site,
+		Login:   *login,
+		Counter: uint32(*counter),
+		Conf:    cfg.Gen,
+	})
+	if err != nil {
+		return err
+	}
+	return writeResult(out, cfg.OutputFormat, configuredLanguage(cfg), result, nil)
+}
+
+// readSecret 终端中无回显读取秘密,否则读取标准输入的第一行
+func readSecret(in *os.File, prompt io.Writer, label string) (string, error) {
+	var secret string
+	if fd := int(in.Fd()); term.IsTerminal(fd) {
+		fmt.Fprintf(prompt, "%s: ", label)
+		b, err := term.ReadPassword(fd)
+		fmt.Fprintln(prompt)
+		if err != nil {
+			return "", err
+		}
+		secret = string(b)
+	} else {
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		secret = strings.TrimRight(line, "\r\n")
+	}
+	if secret == "" {
+		return "", fmt.Errorf("%s is empty", strings.ToLower(label))
+	}
+	return secret, nil
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"flag"
+	"passwdgen/hasher"
+	"strings"
+)
+
+// hashFlags 输出密码哈希的命令行参数,代价参数作为配置项 hash.* 的命令行层
+type hashFlags struct {
+	formats *string
+}
+
+func newHashFlags(fs *flag.FlagSet, cf *configFlags) *hashFlags {
+	defaultParams := hasher.NewDefaultParams()
+	formatNames := make([]string, 0, len(hasher.Formats))
+	for _, f := range hasher.Formats {
+		formatNames = append(formatNames, string(f))
+	}
+	hf := &hashFlags{}
+	hf.formats = fs.String("hash", "", "comma separated hash formats to output, or all: "+strings.Join(formatNames, ", "))
+	fs.Int("bcrypt-cost", defaultParams.BcryptCost, "bcrypt/htpasswd cost")
+	fs.Uint("argon2-time", uint(defaultParams.Argon2Time), "argon2id iterations")
+	fs.Uint("argon2-memory", uint(defaultParams.Argon2Memory), "argon2id memory in KiB")
+	fs.Uint("argon2-threads", uint(defaultParams.Argon2Threads), "argon2id parallelism")
+	fs.Int("scrypt-ln", defaultParams.ScryptLogN, "scrypt log2(N)")
+	fs.Int("scrypt-r", defaultParams.ScryptR, "scrypt r")
+	fs.Int("scrypt-p", defaultParams.ScryptP, "scrypt p")
+	fs.Int("pbkdf2-iterations", defaultParams.PBKDF2Iterations, "PBKDF2-SHA256 iterations")
+	fs.Int("sha512-rounds", defaultParams.SHA512CryptRounds, "SHA-512-crypt rounds")
+	fs.Int("scram-iterations", defaultParams.ScramIterations, "SCRAM-SHA-256 iterations")
+	for name, key := range map[string]string{
+		"bcrypt-cost":       "hash.bcrypt_cost",
+		"argon2-time":       "hash.argon2_time",
+		"argon2-memory":     "hash.argon2_memory",
+		"argon2-threads":    "hash.argon2_threads",
+		"scrypt-ln":         "hash.scrypt_ln",
+		"scrypt-r":          "hash.scrypt_r",
+		"scrypt-p":          "hash.scrypt_p",
+		"pbkdf2-iterations": "hash.pbkdf2_iterations",
+		"sha512-rounds":     "hash.sha512_crypt_rounds",
+		"scram-iterations":  "hash.scram_iterations",
+	} {
+		cf.keys[name] = key
+	}
+	return hf
+}
+
+// hashes 按配置的代价参数计算指定格式的哈希,未指定格式时返回nil
+func (hf *hashFlags) hashes(password string, params *hasher.Params) ([]*hasher.Result, error) {
+	if *hf.formats == "" {
+		return nil, nil
+	}
+	formats, err := hasher.ParseFormats(*hf.formats)
+	if err != nil {
+		return nil, err
+	}
+	return hasher.HashAll(password, formats, params)
+}
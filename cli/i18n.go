@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"passwdgen/i18n"
+	"text/tabwriter"
+)
+
+func init() {
+	commands["i18n"] = runI18n
+}
+
+// runI18n passwdgen i18n check [FILE...],检查语言包是否包含所有消息并列出未使用的消息。
+// 不指定文件时检查内置语言包和用户目录中的语言包
+func runI18n(args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "check" {
+		return fmt.Errorf("usage: %s i18n check [FILE...]", Name)
+	}
+	var reports []*i18n.BundleReport
+	var err error
+	if len(args) > 1 {
+		reports, err = i18n.CheckFiles(args[1:])
+	} else {
+		reports, err = i18n.CheckBundles()
+	}
+	if len(reports) > 0 {
+		if werr := writeBundleReports(out, reports); werr != nil {
+			return werr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	// 缺失消息视为错误,未使用的消息只提示
+	incomplete := 0
+	for _, report := range reports {
+		if len(report.Missing) > 0 {
+			incomplete++
+		}
+	}
+	if incomplete > 0 {
+		return fmt.Errorf("%d locale bundle(s) are missing messages", incomplete)
+	}
+	return nil
+}
+
+func writeBundleReports(out io.Writer, reports []*i18n.BundleReport) error {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "LANGUAGE\tMESSAGES\tMISSING\tUNUSED\tPATH")
+	for _, report := range reports {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\n", report.Tag, report.Messages, len(report.Missing), len(report.Unused), report.Path)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	for _, report := range reports {
+		for _, messageId := range report.Missing {
+			fmt.Fprintf(out, "%s: missing message %s\n", report.Path, messageId)
+		}
+		for _, messageId := range report.Unused {
+			fmt.Fprintf(out, "%s: unused message %s\n", report.Path, messageId)
+		}
+	}
+	return nil
+}
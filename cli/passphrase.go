@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"passwdgen/config"
+	"passwdgen/gen"
+	"passwdgen/wordlist"
+	"strings"
+)
+
+func init() {
+	commands["passphrase"] = runPassphrase
+}
+
+// runPassphrase passwdgen passphrase,从词表中随机选取单词组成口令
+func runPassphrase(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(Name+" passphrase", flag.ContinueOnError)
+	defaultConf := gen.NewDefaultPassphraseConf()
+	words := fs.Int("words", defaultConf.Words, fmt.Sprintf("number of words (%d-%d)", gen.MinPassphraseWords, gen.MaxPassphraseWords))
+	reg := wordlist.Default()
+	list := fs.String("list", "", "word list: "+strings.Join(reg.Names(), ", ")+" (default follows the configured language)")
+	separator := fs.String("separator", defaultConf.Separator, "separator between words")
+	capitalize := fs.Bool("capitalize", false, "capitalise the first letter of each word")
+	digits := fs.Int("digits", 0, fmt.Sprintf("number of random digits to append (0-%d)", gen.MaxPassphraseDigits))
+	format := fs.String("format", "", "output format: plain or json (default from configuration)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	values := make(map[string]string)
+	if *format != "" {
+		values["output.format"] = *format
+	}
+	cfg, err := config.Load(config.FlagLayer(values))
+	if err != nil {
+		return err
+	}
+	conf := &gen.PassphraseConf{Words: *words, WordList: *list, Separator: *separator, Capitalize: *capitalize,
+		Digits: *digits}
+	if conf.WordList == "" {
+		conf.WordList = languageWordList(cfg.Language)
+	}
+	if _, err := reg.Get(conf.WordList); err != nil {
+		// 词表可能因校验失败未被注册,给出具体原因
+		if loadErr := reg.Err(); loadErr != nil {
+			return fmt.Errorf("%s: %w\n%v", conf.WordList, err, loadErr)
+		}
+		return fmt.Errorf("%s: %w", conf.WordList, err)
+	}
+	result, err := gen.GeneratePassphrase(conf)
+	if err != nil {
+		return err
+	}
+	return writeResult(out, cfg.OutputFormat, configuredLanguage(cfg), result, nil)
+}
+
+// languageWordList 返回语言对应的词表,未配置语言时使用默认语言
+func languageWordList(lang string) string {
+	if lang == "" {
+		lang = DefaultLanguage
+	}
+	return wordlist.ForLanguage(lang)
+}
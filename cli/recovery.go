@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"passwdgen/config"
+	"passwdgen/gen"
+	"passwdgen/sheet"
+	"time"
+)
+
+func init() {
+	commands["recovery"] = runRecovery
+}
+
+// recoveryOutput 恢复码的JSON输出
+type recoveryOutput struct {
+	Codes   []string `json:"codes"`
+	Entropy float64  `json:"entropy"`
+}
+
+// runRecovery passwdgen recovery,生成可打印的恢复码
+func runRecovery(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(Name+" recovery", flag.ContinueOnError)
+	defaultConf := gen.NewDefaultRecoveryCodeConf()
+	count := fs.Int("count", defaultConf.Count, "number of codes")
+	groups := fs.Int("groups", defaultConf.Groups, "number of groups per code")
+	groupSize := fs.Int("group-size", defaultConf.GroupSize, "characters per group")
+	separator := fs.String("separator", defaultConf.Separator, "group separator")
+	alphabet := fs.String("alphabet", gen.DefaultRecoveryCodeAlphabet(), "code alphabet")
+	title := fs.String("title", "Recovery codes", "sheet title")
+	sheetFormat := fs.String("sheet", "", "write a printable sheet: text, pdf or png")
+	output := fs.String("o", "", "sheet output file (default stdout)")
+	outputFormat := fs.String("output", "", "output format without -sheet: plain or json (default from configuration)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	values := make(map[string]string)
+	if *outputFormat != "" {
+		values["output.format"] = *outputFormat
+	}
+	cfg, err := config.Load(config.FlagLayer(values))
+	if err != nil {
+		return err
+	}
+	result, err := gen.GenerateRecoveryCodes(&gen.RecoveryCodeConf{
+		Count:     *count,
+		Groups:    *groups,
+		GroupSize: *groupSize,
+		Separator: *separator,
+		Alphabet:  *alphabet,
+	})
+	if err != nil {
+		return err
+	}
+	if *sheetFormat != "" {
+		format, err := sheet.ParseFormat(*sheetFormat)
+		if err != nil {
+			return fmt.Errorf("%s: %w", *sheetFormat, err)
+		}
+		s := &sheet.Sheet{
+			Title: *title,
+			Notes: []string{
+				"Generated " + time.Now().Format("2006-01-02 15:04"),
+				"Each code can be used only once.",
+			},
+			Items: result.Codes,
+		}
+		if err := s.Check(format); err != nil {
+			return err
+		}
+		if *output == "" {
+			return s.Write(out, format)
+		}
+		f, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		if err := s.Write(f, format); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	if cfg.OutputFormat == config.OutputFormatJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(&recoveryOutput{Codes: result.Codes, Entropy: result.Entropy})
+	}
+	for _, code := range result.Codes {
+		if _, err := fmt.Fprintln(out, code); err != nil {
+			return err
+		}
+	}
+	return nil
+}
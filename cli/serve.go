@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"passwdgen/config"
+	"passwdgen/server"
+	"strings"
+	"syscall"
+)
+
+// EnvServeToken 服务的Bearer token,避免在命令行参数中暴露
+const EnvServeToken = config.EnvPrefix + "SERVE_TOKEN"
+
+func init() {
+	commands["serve"] = runServe
+}
+
+// runServe passwdgen serve
+func runServe(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(Name+" serve", flag.ContinueOnError)
+	addr := fs.String("listen", server.DefaultAddr, "loopback address to listen on")
+	socket := fs.String("socket", "", "unix socket path to listen on instead of -listen")
+	tokenFile := fs.String("token-file", "", "file containing the bearer token (or set "+EnvServeToken+")")
+	maxBody := fs.Int64("max-body", server.DefaultMaxBodyBytes, "maximum request body size in bytes")
+	rate := fs.Float64("rate", server.DefaultRate, "allowed requests per second")
+	burst := fs.Int("burst", server.DefaultBurst, "allowed request burst")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	token := os.Getenv(EnvServeToken)
+	if *tokenFile != "" {
+		b, err := os.ReadFile(*tokenFile)
+		if err != nil {
+			return err
+		}
+		token = strings.TrimSpace(string(b))
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	srv := server.New(server.Options{
+		Addr:         *addr,
+		Socket:       *socket,
+		Token:        token,
+		MaxBodyBytes: *maxBody,
+		Rate:         *rate,
+		Burst:        *burst,
+		Defaults:     cfg.Gen,
+	})
+	l, err := srv.Listen()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "listening on %s (auth: %t)\n", l.Addr(), token != "")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return srv.Serve(ctx, l)
+}
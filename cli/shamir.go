@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"passwdgen/config"
+	"passwdgen/gen"
+	"passwdgen/shamir"
+)
+
+func init() {
+	commands["split"] = runSplit
+	commands["combine"] = runCombine
+}
+
+// splitOutput 拆分结果的JSON输出
+type splitOutput struct {
+	// 使用 -generate 时生成的秘密
+	Secret    string   `json:"secret,omitempty"`
+	Threshold int      `json:"threshold"`
+	Shares    []string `json:"shares"`
+}
+
+// runSplit passwdgen split,把秘密拆分为n个分片,任意k个可以恢复
+func runSplit(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(Name+" split", flag.ContinueOnError)
+	cf := newConfigFlags(fs)
+	n := fs.Int("n", 5, "number of shares")
+	k := fs.Int("k", 3, "number of shares required to combine")
+	generate := fs.Bool("generate", false, "generate a password and split it; the password is printed before the shares")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	cfg, err := cf.resolve()
+	if err != nil {
+		return err
+	}
+	var secret, generated string
+	if *generate {
+		result, err := gen.GeneratePassword(cfg.Gen)
+		if err != nil {
+			return err
+		}
+		secret, generated = result.Password, result.Password
+	} else if secret, err = readSecret(os.Stdin, os.Stderr, "Secret"); err != nil {
+		return err
+	}
+	shares, err := shamir.Split([]byte(secret), *n, *k)
+	if err != nil {
+		return err
+	}
+	so := &splitOutput{Secret: generated, Threshold: *k}
+	for _, share := range shares {
+		so.Shares = append(so.Shares, share.String())
+	}
+	if cfg.OutputFormat == config.OutputFormatJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(so)
+	}
+	if generated != "" {
+		if _, err := fmt.Fprintf(out, "%s\n\n", generated); err != nil {
+			return err
+		}
+	}
+	for _, share := range so.Shares {
+		if _, err := fmt.Fprintln(out, share); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runCombine passwdgen combine,由参数或标准输入(每行一个)中的分片恢复秘密
+func runCombine(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet(Name+" combine", flag.ContinueOnError)
+	outputFormat := fs.String("output", "", "output format: plain or json (default from configuration)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	values := make(map[string]string)
+	if *outputFormat != "" {
+		values["output.format"] = *outputFormat
+	}
+	cfg, err := config.Load(config.FlagLayer(values))
+	if err != nil {
+		return err
+	}
+	texts := fs.Args()
+	if len(texts) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			texts = append(texts, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+	shares, err := shamir.ParseShares(texts)
+	if err != nil {
+		return err
+	}
+	secret, err := shamir.Combine(shares)
+	if err != nil {
+		return err
+	}
+	if cfg.OutputFormat == config.OutputFormatJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(map[string]string{"secret": string(secret)})
+	}
+	_, err = fmt.Fprintln(out, string(secret))
+	return err
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"passwdgen/wordlist"
+	"text/tabwriter"
+)
+
+func init() {
+	commands["wordlist"] = runWordList
+}
+
+// runWordList passwdgen wordlist list|check,列出可用词表或校验词表文件
+func runWordList(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: %s wordlist list | wordlist check FILE...", Name)
+	}
+	switch args[0] {
+	case "list":
+		if len(args) > 1 {
+			return fmt.Errorf("unexpected argument %q", args[1])
+		}
+		reg := wordlist.Default()
+		var lists []*wordlist.List
+		for _, name := range reg.Names() {
+			list, err := reg.Get(name)
+			if err != nil {
+				return err
+			}
+			lists = append(lists, list)
+		}
+		if err := writeWordLists(out, lists); err != nil {
+			return err
+		}
+		// 校验失败的用户词表
+		return reg.Err()
+	case "check":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: %s wordlist check FILE...", Name)
+		}
+		var lists []*wordlist.List
+		var errs []error
+		for _, path := range args[1:] {
+			list, err := wordlist.ParseFile(path)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			lists = append(lists, list)
+		}
+		if len(lists) > 0 {
+			if err := writeWordLists(out, lists); err != nil {
+				return err
+			}
+		}
+		if len(errs) > 0 {
+			return &wordlist.LoadError{Errors: errs}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown wordlist command %q", args[0])
+	}
+}
+
+func writeWordLists(out io.Writer, lists []*wordlist.List) error {
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tSOURCE\tWORDS\tBITS/WORD\tPATH")
+	for _, list := range lists {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%.2f\t%s\n", list.Name, list.Source, len(list.Words), list.Entropy(), list.Path)
+	}
+	return tw.Flush()
+}
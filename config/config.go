@@ -0,0 +1,574 @@
+package config
+
+import (
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"golang.org/x/text/language"
+	"os"
+	"passwdgen/gen"
+	"passwdgen/hasher"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	AppDirName     = "passwdgen"
+	ConfigFileName = "config.toml"
+	// EnvPrefix 环境变量前缀,例如 PASSWDGEN_GENERATION_LENGTH
+	EnvPrefix = "PASSWDGEN_"
+	// EnvConfigFile 指定配置文件路径的环境变量
+	EnvConfigFile = EnvPrefix + "CONFIG"
+)
+
+const (
+	OutputFormatPlain = "plain"
+	OutputFormatJSON  = "json"
+)
+
+// Source 配置值的来源
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceProfile Source = "profile"
+	SourceFlag    Source = "flag"
+)
+
+// Config 合并后的有效配置
+type Config struct {
+	// 密码生成默认配置
+	Gen *gen.PasswdGenConf
+	// 命令行输出格式
+	OutputFormat string
+	// 复制后自动清空剪贴板的时长,0表示不清空
+	ClipboardTimeout time.Duration
+	// 历史记录文件,为空时历史记录只保存在内存中。文件包含明文密码
+	HistoryPath string
+	// 界面语言,例如 zh_CN
+	Language string
+	// 界面和命令行计算密码哈希的代价参数
+	Hash *hasher.Params
+	// 每个配置项的来源
+	origins map[string]Origin
+}
+
+// Origin 配置项的来源描述
+type Origin struct {
+	Source Source
+	// 文件路径、环境变量名或配置名称
+	Location string
+}
+
+func (o Origin) String() string {
+	if o.Location == "" {
+		return string(o.Source)
+	}
+	return fmt.Sprintf("%s %s", o.Source, o.Location)
+}
+
+// Layer 一层配置,后面的层覆盖前面的层
+type Layer struct {
+	Source   Source
+	Location string
+	Values   map[string]string
+	// 每个值单独的位置,例如环境变量名
+	locations map[string]string
+}
+
+// Problem 单个配置项的校验问题
+type Problem struct {
+	Key    string
+	Origin Origin
+	Reason string
+	// 导致问题的错误,显示时接在 Reason 之后,可以按错误码翻译
+	Err error
+}
+
+func (p Problem) Error() string {
+	return p.ErrorWith(func(err error) string {
+		return err.Error()
+	})
+}
+
+// ErrorWith 使用 describe 显示 Err 后返回问题的文本
+func (p Problem) ErrorWith(describe func(err error) string) string {
+	reason := p.Reason
+	if p.Err != nil {
+		if reason != "" {
+			reason += ": "
+		}
+		reason += describe(p.Err)
+	}
+	if p.Key == "" {
+		return reason
+	}
+	return fmt.Sprintf("%s (%s): %s", p.Key, p.Origin, reason)
+}
+
+func (p Problem) Unwrap() error {
+	return p.Err
+}
+
+// ValidationError 配置校验错误,包含所有发现的问题
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (ve *ValidationError) Error() string {
+	return ve.ErrorWith(func(err error) string {
+		return err.Error()
+	})
+}
+
+// ErrorWith 使用 describe 显示各个问题的错误后返回文本,界面和命令行借此翻译其中带错误码的错误
+func (ve *ValidationError) ErrorWith(describe func(err error) string) string {
+	lines := make([]string, 0, len(ve.Problems)+1)
+	lines = append(lines, fmt.Sprintf("invalid configuration (%d problem(s)):", len(ve.Problems)))
+	for _, p := range ve.Problems {
+		lines = append(lines, "  "+p.ErrorWith(describe))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DefaultPath 返回默认配置文件路径,可以通过 PASSWDGEN_CONFIG 覆盖
+func DefaultPath() (string, error) {
+	if path, ok := os.LookupEnv(EnvConfigFile); ok && path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, AppDirName, ConfigFileName), nil
+}
+
+// Default 返回内置默认配置
+func Default() *Config {
+	c := &Config{Gen: &gen.PasswdGenConf{}, Hash: &hasher.Params{}, origins: make(map[string]Origin)}
+	for _, f := range fields {
+		_ = f.set(c, f.def())
+		c.origins[f.key] = Origin{Source: SourceDefault}
+	}
+	return c
+}
+
+// Keys 返回所有配置项名称
+func Keys() []string {
+	keys := make([]string, 0, len(fields))
+	for _, f := range fields {
+		keys = append(keys, f.key)
+	}
+	return keys
+}
+
+// EnvName 返回配置项对应的环境变量名
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// Load 读取默认配置文件和环境变量,再叠加额外的层(例如命令行参数)
+func Load(extra ...Layer) (*Config, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	fileLayer, err := FileLayer(path)
+	if err != nil {
+		return nil, err
+	}
+	layers := append([]Layer{fileLayer, EnvLayer(os.Environ())}, extra...)
+	return Resolve(layers...)
+}
+
+// FileLayer 读取TOML配置文件,文件不存在时返回空层
+func FileLayer(path string) (Layer, error) {
+	layer := Layer{Source: SourceFile, Location: path, Values: make(map[string]string)}
+	raw := make(map[string]interface{})
+	_, err := toml.DecodeFile(path, &raw)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return layer, nil
+		}
+		return layer, fmt.Errorf("%s: %w", path, err)
+	}
+	var problems []Problem
+	flatten("", raw, func(key string, value interface{}) {
+		switch v := value.(type) {
+		case string:
+			layer.Values[key] = v
+		case int64, bool, float64:
+			layer.Values[key] = fmt.Sprint(v)
+		case []interface{}:
+			// 字符串数组按逗号连接,例如 classes = ["greek", "hex"]
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				if str, ok := item.(string); ok {
+					items = append(items, str)
+				}
+			}
+			if len(items) != len(v) {
+				problems = append(problems, Problem{Key: key, Origin: Origin{Source: SourceFile, Location: path},
+					Reason: "arrays must contain only strings"})
+				return
+			}
+			layer.Values[key] = strings.Join(items, ",")
+		default:
+			problems = append(problems, Problem{Key: key, Origin: Origin{Source: SourceFile, Location: path},
+				Reason: fmt.Sprintf("unsupported value type %T", value)})
+		}
+	})
+	if len(problems) > 0 {
+		return layer, &ValidationError{Problems: problems}
+	}
+	return layer, nil
+}
+
+// EnvLayer 从环境变量中读取 PASSWDGEN_* 配置
+func EnvLayer(environ []string) Layer {
+	layer := Layer{Source: SourceEnv, Values: make(map[string]string), locations: make(map[string]string)}
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	for _, f := range fields {
+		name := EnvName(f.key)
+		if v, ok := env[name]; ok {
+			layer.Values[f.key] = v
+			layer.locations[f.key] = name
+		}
+	}
+	return layer
+}
+
+// ProfileLayer 把命名配置转换为配置层
+func ProfileLayer(name string, conf *gen.PasswdGenConf) Layer {
+	return Layer{Source: SourceProfile, Location: name, Values: GenValues(conf)}
+}
+
+// FlagLayer 命令行参数配置层
+func FlagLayer(values map[string]string) Layer {
+	return Layer{Source: SourceFlag, Values: values}
+}
+
+// GenValues 把密码生成配置转换为配置项
+func GenValues(conf *gen.PasswdGenConf) map[string]string {
+	c := &Config{Gen: conf}
+	values := make(map[string]string)
+	for _, f := range fields {
+		if strings.HasPrefix(f.key, genKeyPrefix) {
+			values[f.key] = f.get(c)
+		}
+	}
+	return values
+}
+
+// Resolve 按顺序合并配置层并校验
+func Resolve(layers ...Layer) (*Config, error) {
+	c := Default()
+	var problems []Problem
+	for _, layer := range layers {
+		keys := make([]string, 0, len(layer.Values))
+		for key := range layer.Values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			origin := Origin{Source: layer.Source, Location: layer.Location}
+			if loc, ok := layer.locations[key]; ok {
+				origin.Location = loc
+			}
+			f, ok := fieldByKey[key]
+			if !ok {
+				problems = append(problems, Problem{Key: key, Origin: origin, Reason: "unknown configuration key"})
+				continue
+			}
+			if err := f.set(c, layer.Values[key]); err != nil {
+				problems = append(problems, Problem{Key: key, Origin: origin, Err: err})
+				continue
+			}
+			c.origins[key] = origin
+		}
+	}
+	if len(problems) == 0 {
+		problems = c.validate()
+	}
+	if len(problems) > 0 {
+		return c, &ValidationError{Problems: problems}
+	}
+	return c, nil
+}
+
+// Origin 返回配置项的来源
+func (c *Config) Origin(key string) Origin {
+	return c.origins[key]
+}
+
+// Value 返回配置项的字符串形式
+func (c *Config) Value(key string) string {
+	if f, ok := fieldByKey[key]; ok {
+		return f.get(c)
+	}
+	return ""
+}
+
+// validate 校验配置项之间的约束
+func (c *Config) validate() []Problem {
+	var problems []Problem
+	conf := *c.Gen
+	if _, err := gen.GeneratePassword(&conf); err != nil {
+		problems = append(problems, Problem{Reason: "generation settings cannot produce a password", Err: err})
+	}
+	if err := c.Hash.Validate(); err != nil {
+		problems = append(problems, Problem{Reason: "hash settings are invalid", Err: err})
+	}
+	return problems
+}
+
+func flatten(prefix string, raw map[string]interface{}, visit func(key string, value interface{})) {
+	for k, v := range raw {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if table, ok := v.(map[string]interface{}); ok {
+			flatten(key, table, visit)
+			continue
+		}
+		visit(key, v)
+	}
+}
+
+const (
+	genKeyPrefix  = "generation."
+	hashKeyPrefix = "hash."
+)
+
+type field struct {
+	key string
+	def func() string
+	set func(c *Config, value string) error
+	get func(c *Config) string
+}
+
+var fields = []*field{
+	{
+		key: genKeyPrefix + "length",
+		def: func() string { return strconv.Itoa(int(gen.DefaultLength)) },
+		set: func(c *Config, value string) error {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 1 || n > 255 {
+				return fmt.Errorf("invalid value %q: must be an integer between 1 and 255", value)
+			}
+			c.Gen.Length = uint8(n)
+			return nil
+		},
+		get: func(c *Config) string { return strconv.Itoa(int(c.Gen.Length)) },
+	},
+	boolField(genKeyPrefix+"number", func(c *Config) *bool { return &c.Gen.EnableNumber }),
+	boolField(genKeyPrefix+"lowercase", func(c *Config) *bool { return &c.Gen.EnableLowercase }),
+	boolField(genKeyPrefix+"uppercase", func(c *Config) *bool { return &c.Gen.EnableUppercase }),
+	boolField(genKeyPrefix+"duplicate", func(c *Config) *bool { return &c.Gen.EnableDuplicate }),
+	stringField(genKeyPrefix+"include", gen.DefaultIncludeSpecialCharSet, func(c *Config) *string {
+		return &c.Gen.IncludeSpecialCharSet
+	}),
+	stringField(genKeyPrefix+"exclude", gen.DefaultExcludeSpecialCharSet, func(c *Config) *string {
+		return &c.Gen.ExcludeSpecialCharSet
+	}),
+	{
+		key: genKeyPrefix + "classes",
+		def: func() string { return "" },
+		set: func(c *Config, value string) error {
+			classes, err := gen.ParseCharClasses(value)
+			if err != nil {
+				return fmt.Errorf("invalid value %q: must be a comma separated list of %s", value,
+					strings.Join(gen.NamedCharClasses, ", "))
+			}
+			c.Gen.Classes = classes
+			return nil
+		},
+		get: func(c *Config) string { return strings.Join(c.Gen.Classes, ",") },
+	},
+	{
+		key: "output.format",
+		def: func() string { return OutputFormatPlain },
+		set: func(c *Config, value string) error {
+			value = strings.ToLower(strings.TrimSpace(value))
+			if value != OutputFormatPlain && value != OutputFormatJSON {
+				return fmt.Errorf("invalid value %q: must be %q or %q", value, OutputFormatPlain, OutputFormatJSON)
+			}
+			c.OutputFormat = value
+			return nil
+		},
+		get: func(c *Config) string { return c.OutputFormat },
+	},
+	{
+		key: "clipboard.timeout",
+		def: func() string { return "0s" },
+		set: func(c *Config, value string) error {
+			duration := strings.TrimSpace(value)
+			// 纯数字按秒处理
+			if n, err := strconv.Atoi(duration); err == nil {
+				duration = strconv.Itoa(n) + "s"
+			}
+			d, err := time.ParseDuration(duration)
+			if err != nil || d < 0 {
+				return fmt.Errorf("invalid value %q: must be a non-negative duration such as 30s or 2m", value)
+			}
+			c.ClipboardTimeout = d
+			return nil
+		},
+		get: func(c *Config) string { return c.ClipboardTimeout.String() },
+	},
+	{
+		key: "history.path",
+		def: func() string { return "" },
+		set: func(c *Config, value string) error {
+			value = strings.TrimSpace(value)
+			if strings.HasPrefix(value, "~"+string(filepath.Separator)) || value == "~" {
+				home, err := os.UserHomeDir()
+				if err != nil {
+					return fmt.Errorf("invalid value %q: %v", value, err)
+				}
+				value = filepath.Join(home, strings.TrimPrefix(value, "~"))
+			}
+			if value != "" && !filepath.IsAbs(value) {
+				return fmt.Errorf("invalid value %q: must be an absolute path", value)
+			}
+			c.HistoryPath = value
+			return nil
+		},
+		get: func(c *Config) string { return c.HistoryPath },
+	},
+	{
+		key: "language",
+		def: func() string { return "zh_CN" },
+		set: func(c *Config, value string) error {
+			value = strings.TrimSpace(value)
+			if _, err := language.Parse(strings.ReplaceAll(value, "_", "-")); err != nil {
+				return fmt.Errorf("invalid value %q: must be a language tag such as zh_CN or en_US", value)
+			}
+			c.Language = value
+			return nil
+		},
+		get: func(c *Config) string { return c.Language },
+	},
+	intField(hashKeyPrefix+"bcrypt_cost", hasher.NewDefaultParams().BcryptCost, func(c *Config) *int {
+		return &c.Hash.BcryptCost
+	}),
+	uint32Field(hashKeyPrefix+"argon2_time", hasher.NewDefaultParams().Argon2Time, func(c *Config) *uint32 {
+		return &c.Hash.Argon2Time
+	}),
+	uint32Field(hashKeyPrefix+"argon2_memory", hasher.NewDefaultParams().Argon2Memory, func(c *Config) *uint32 {
+		return &c.Hash.Argon2Memory
+	}),
+	{
+		key: hashKeyPrefix + "argon2_threads",
+		def: func() string { return strconv.Itoa(int(hasher.NewDefaultParams().Argon2Threads)) },
+		set: func(c *Config, value string) error {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 1 || n > 255 {
+				return fmt.Errorf("invalid value %q: must be an integer between 1 and 255", value)
+			}
+			c.Hash.Argon2Threads = uint8(n)
+			return nil
+		},
+		get: func(c *Config) string { return strconv.Itoa(int(c.Hash.Argon2Threads)) },
+	},
+	intField(hashKeyPrefix+"scrypt_ln", hasher.NewDefaultParams().ScryptLogN, func(c *Config) *int {
+		return &c.Hash.ScryptLogN
+	}),
+	intField(hashKeyPrefix+"scrypt_r", hasher.NewDefaultParams().ScryptR, func(c *Config) *int {
+		return &c.Hash.ScryptR
+	}),
+	intField(hashKeyPrefix+"scrypt_p", hasher.NewDefaultParams().ScryptP, func(c *Config) *int {
+		return &c.Hash.ScryptP
+	}),
+	intField(hashKeyPrefix+"pbkdf2_iterations", hasher.NewDefaultParams().PBKDF2Iterations, func(c *Config) *int {
+		return &c.Hash.PBKDF2Iterations
+	}),
+	intField(hashKeyPrefix+"sha512_crypt_rounds", hasher.NewDefaultParams().SHA512CryptRounds, func(c *Config) *int {
+		return &c.Hash.SHA512CryptRounds
+	}),
+	intField(hashKeyPrefix+"scram_iterations", hasher.NewDefaultParams().ScramIterations, func(c *Config) *int {
+		return &c.Hash.ScramIterations
+	}),
+}
+
+var fieldByKey = func() map[string]*field {
+	m := make(map[string]*field, len(fields))
+	for _, f := range fields {
+		m[f.key] = f
+	}
+	return m
+}()
+
+func boolField(key string, ptr func(c *Config) *bool) *field {
+	defaultConf := gen.NewDefaultPasswdGenConf()
+	return &field{
+		key: key,
+		def: func() string {
+			return strconv.FormatBool(*ptr(&Config{Gen: defaultConf}))
+		},
+		set: func(c *Config, value string) error {
+			b, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("invalid value %q: must be true or false", value)
+			}
+			*ptr(c) = b
+			return nil
+		},
+		get: func(c *Config) string { return strconv.FormatBool(*ptr(c)) },
+	}
+}
+
+// intField 正整数配置项,具体范围由 validate 校验
+func intField(key string, def int, ptr func(c *Config) *int) *field {
+	return &field{
+		key: key,
+		def: func() string { return strconv.Itoa(def) },
+		set: func(c *Config, value string) error {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid value %q: must be a positive integer", value)
+			}
+			*ptr(c) = n
+			return nil
+		},
+		get: func(c *Config) string { return strconv.Itoa(*ptr(c)) },
+	}
+}
+
+func uint32Field(key string, def uint32, ptr func(c *Config) *uint32) *field {
+	return &field{
+		key: key,
+		def: func() string { return strconv.FormatUint(uint64(def), 10) },
+		set: func(c *Config, value string) error {
+			n, err := strconv.ParseUint(strings.TrimSpace(value), 10, 32)
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid value %q: must be a positive integer", value)
+			}
+			*ptr(c) = uint32(n)
+			return nil
+		},
+		get: func(c *Config) string { return strconv.FormatUint(uint64(*ptr(c)), 10) },
+	}
+}
+
+func stringField(key string, def string, ptr func(c *Config) *string) *field {
+	return &field{
+		key: key,
+		def: func() string { return def },
+		set: func(c *Config, value string) error {
+			*ptr(c) = value
+			return nil
+		},
+		get: func(c *Config) string { return *ptr(c) },
+	}
+}
@@ -0,0 +1,63 @@
+package errcode
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Code 错误码,界面和命令行按错误码显示对应语言的提示。
+// 各个包定义并登记自己的错误码,错误码在所有包中唯一
+type Code string
+
+// Error 带错误码的错误,显示文本由 i18n 按错误码提供
+type Error struct {
+	Code Code
+}
+
+func (e *Error) Error() string {
+	return strings.ReplaceAll(string(e.Code), "_", " ")
+}
+
+// New 返回带错误码的错误
+func New(code Code) *Error {
+	return &Error{Code: code}
+}
+
+var (
+	mu         sync.RWMutex
+	messageIds = make(map[Code]string)
+)
+
+// Register 登记错误码对应提示的消息ID,各个包在初始化时登记自己的错误码,重复登记时panic
+func Register(code Code, messageId string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := messageIds[code]; ok {
+		panic(fmt.Sprintf("errcode: duplicate code %q", code))
+	}
+	messageIds[code] = messageId
+}
+
+// MessageId 返回错误码登记的消息ID
+func MessageId(code Code) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	messageId, ok := messageIds[code]
+	return messageId, ok
+}
+
+// Codes 返回已登记的错误码,按错误码排序
+func Codes() []Code {
+	mu.RLock()
+	codes := make([]Code, 0, len(messageIds))
+	for code := range messageIds {
+		codes = append(codes, code)
+	}
+	mu.RUnlock()
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i] < codes[j]
+	})
+	return codes
+}
@@ -0,0 +1,100 @@
+package gen
+
+import (
+	"github.com/rivo/uniseg"
+	"strings"
+)
+
+// 内置字符类的名称,与按字符类别着色的 CharClass 无关
+const (
+	ClassNameLatin1   = "latin1"
+	ClassNameCyrillic = "cyrillic"
+	ClassNameGreek    = "greek"
+	ClassNameHex      = "hex"
+	ClassNameBase58   = "base58"
+	ClassNameEmoji    = "emoji"
+)
+
+// NamedCharClasses 所有内置字符类的名称
+var NamedCharClasses = []string{
+	ClassNameLatin1,
+	ClassNameCyrillic,
+	ClassNameGreek,
+	ClassNameHex,
+	ClassNameBase58,
+	ClassNameEmoji,
+}
+
+var invalidCharClassError = newError(ErrorInvalidCharClass)
+
+var namedCharClassSets = map[string]string{
+	// Latin-1补充中的字母,不含 × 和 ÷
+	ClassNameLatin1: runeRange(0xc0, 0xff, 0xd7, 0xf7),
+	// 基本西里尔字母及 Ёё
+	ClassNameCyrillic: runeRange(0x410, 0x44f) + "Ёё",
+	// 希腊字母,不含保留码位和词尾 ς
+	ClassNameGreek:  runeRange(0x391, 0x3a9, 0x3a2) + runeRange(0x3b1, 0x3c9, 0x3c2),
+	ClassNameHex:    "0123456789abcdef",
+	ClassNameBase58: Base58Alphabet,
+	// 表情符号区块,均为单个码点
+	ClassNameEmoji: runeRange(0x1f600, 0x1f64f),
+}
+
+// NamedCharClass 返回内置字符类包含的字符
+func NamedCharClass(name string) (string, error) {
+	chars, ok := namedCharClassSets[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return "", invalidCharClassError
+	}
+	return chars, nil
+}
+
+// ParseCharClasses 解析逗号分隔的字符类名称
+func ParseCharClasses(value string) ([]string, error) {
+	var classes []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, err := NamedCharClass(name); err != nil {
+			return nil, err
+		}
+		classes = append(classes, name)
+	}
+	return classes, nil
+}
+
+// Graphemes 把字符串拆分为用户感知的字符(字素簇),例如带修饰符的表情或组合重音
+func Graphemes(s string) []string {
+	var graphemes []string
+	state := -1
+	for len(s) > 0 {
+		var cluster string
+		cluster, s, _, state = uniseg.FirstGraphemeClusterInString(s, state)
+		graphemes = append(graphemes, cluster)
+	}
+	return graphemes
+}
+
+// GraphemeCount 返回字符串的字符数
+func GraphemeCount(s string) int {
+	return uniseg.GraphemeClusterCount(s)
+}
+
+func runeRange(from, to rune, skip ...rune) string {
+	var b strings.Builder
+	for r := from; r <= to; r++ {
+		skipped := false
+		for _, s := range skip {
+			if r == s {
+				skipped = true
+				break
+			}
+		}
+		if !skipped {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
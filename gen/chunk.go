@@ -0,0 +1,54 @@
+package gen
+
+import (
+	"strings"
+	"unicode"
+)
+
+// CharClass 字符类别,用于分组着色显示
+type CharClass int
+
+const (
+	CharClassDigit CharClass = iota
+	CharClassLowercase
+	CharClassUppercase
+	CharClassSymbol
+)
+
+// DefaultChunkSeparator 分组之间的分隔符
+const DefaultChunkSeparator = " "
+
+// ClassOf 返回字符的类别
+func ClassOf(r rune) CharClass {
+	switch {
+	case unicode.IsDigit(r):
+		return CharClassDigit
+	case unicode.IsLower(r):
+		return CharClassLowercase
+	case unicode.IsUpper(r):
+		return CharClassUppercase
+	}
+	return CharClassSymbol
+}
+
+// Chunk 按字符(字素簇而非字节)把密码分为每组size个字符,size<=0时不分组
+func Chunk(passwd string, size int) []string {
+	graphemes := Graphemes(passwd)
+	if size <= 0 || len(graphemes) <= size {
+		return []string{passwd}
+	}
+	chunks := make([]string, 0, (len(graphemes)+size-1)/size)
+	for start := 0; start < len(graphemes); start += size {
+		end := start + size
+		if end > len(graphemes) {
+			end = len(graphemes)
+		}
+		chunks = append(chunks, strings.Join(graphemes[start:end], ""))
+	}
+	return chunks
+}
+
+// FormatChunked 返回以分隔符连接的分组密码,仅用于显示,复制时仍使用原始密码
+func FormatChunked(passwd string, size int) string {
+	return strings.Join(Chunk(passwd, size), DefaultChunkSeparator)
+}
@@ -0,0 +1,181 @@
+package gen
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"golang.org/x/crypto/argon2"
+	"strings"
+)
+
+// 派生参数固定不变,修改会导致所有派生密码变化
+const (
+	deriveVersion      = "passwdgen.derive.v1"
+	deriveArgon2Time   = 3
+	deriveArgon2Memory = 64 * 1024
+	deriveArgon2Thread = 1
+	deriveKeyLength    = 64
+	// MaxDeriveAttempts 派生时满足字符类约束的最大尝试次数
+	MaxDeriveAttempts = 1000
+)
+
+var invalidDeriveInputError = newError(ErrorInvalidDeriveInput)
+var deriveUnsatisfiedError = newError(ErrorDeriveUnsatisfied)
+
+// DeriveConf 由主密码、站点、登录名和计数器确定性派生密码,不保存任何状态
+type DeriveConf struct {
+	Master  string
+	Site    string
+	Login   string
+	Counter uint32
+	// 派生密码使用的字符集和字符类约束
+	Conf *PasswdGenConf
+}
+
+// DerivePassword 使用Argon2id对 主密码+站点+登录名+计数器 做密钥派生,
+// 再以拒绝采样的方式无偏地映射到字符集,并保证每个启用的字符类至少出现一次
+func DerivePassword(dc *DeriveConf) (*PasswdGenResult, error) {
+	if dc == nil || dc.Master == "" || strings.TrimSpace(dc.Site) == "" {
+		return nil, invalidDeriveInputError
+	}
+	conf := dc.Conf
+	if conf == nil {
+		conf = NewDefaultPasswdGenConf()
+	}
+	if conf.Length <= 0 {
+		return nil, invalidLengthError
+	}
+	if !conf.hasCharSource() {
+		return nil, optionsError
+	}
+	charSet, err := buildCharSet(conf)
+	if err != nil {
+		return nil, err
+	}
+	classes := deriveClasses(conf, charSet)
+	if len(classes) > int(conf.Length) {
+		return nil, deriveUnsatisfiedError
+	}
+	key := argon2.IDKey([]byte(dc.Master), deriveSalt(dc), deriveArgon2Time, deriveArgon2Memory, deriveArgon2Thread,
+		deriveKeyLength)
+	stream := newDeriveStream(key)
+	for attempt := 0; attempt < MaxDeriveAttempts; attempt++ {
+		candidate := deriveCandidate(stream, charSet, conf)
+		if satisfiesClasses(candidate, classes) {
+			entropy := charSetEntropy(len(charSet), len(candidate), conf.EnableDuplicate)
+			return newPasswdGenResult(strings.Join(candidate, ""), entropy), nil
+		}
+	}
+	return nil, deriveUnsatisfiedError
+}
+
+// deriveSalt 各字段带长度前缀拼接,避免 站点+登录名 的拼接歧义
+func deriveSalt(dc *DeriveConf) []byte {
+	var salt []byte
+	for _, field := range []string{deriveVersion, strings.ToLower(strings.TrimSpace(dc.Site)), dc.Login} {
+		salt = binary.BigEndian.AppendUint32(salt, uint32(len(field)))
+		salt = append(salt, field...)
+	}
+	return binary.BigEndian.AppendUint32(salt, dc.Counter)
+}
+
+// deriveClasses 返回字符集中存在的每个启用字符类
+func deriveClasses(conf *PasswdGenConf, charSet []string) []map[string]struct{} {
+	var classes []map[string]struct{}
+	add := func(enabled bool, class string) {
+		if !enabled {
+			return
+		}
+		members := make(map[string]struct{})
+		for _, c := range Graphemes(class) {
+			members[c] = struct{}{}
+		}
+		available := make(map[string]struct{})
+		for _, c := range charSet {
+			if _, ok := members[c]; ok {
+				available[c] = struct{}{}
+			}
+		}
+		if len(available) > 0 {
+			classes = append(classes, available)
+		}
+	}
+	add(conf.EnableNumber, DefaultNumberCharSet)
+	add(conf.EnableLowercase, DefaultLowercaseCharSet)
+	add(conf.EnableUppercase, strings.ToUpper(DefaultLowercaseCharSet))
+	add(len(conf.IncludeSpecialCharSet) > 0, conf.IncludeSpecialCharSet)
+	for _, name := range conf.Classes {
+		chars, _ := NamedCharClass(name)
+		add(true, chars)
+	}
+	return classes
+}
+
+func deriveCandidate(stream *deriveStream, charSet []string, conf *PasswdGenConf) []string {
+	candidate := make([]string, 0, conf.Length)
+	used := make(map[string]struct{})
+	for len(candidate) < int(conf.Length) {
+		c := charSet[stream.intn(len(charSet))]
+		if !conf.EnableDuplicate {
+			if _, ok := used[c]; ok {
+				continue
+			}
+			used[c] = struct{}{}
+		}
+		candidate = append(candidate, c)
+	}
+	return candidate
+}
+
+func satisfiesClasses(candidate []string, classes []map[string]struct{}) bool {
+	for _, class := range classes {
+		found := false
+		for _, c := range candidate {
+			if _, ok := class[c]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// deriveStream 以HMAC-SHA256计数器模式从派生密钥扩展出确定性的字节流
+type deriveStream struct {
+	key     []byte
+	counter uint64
+	buf     []byte
+}
+
+func newDeriveStream(key []byte) *deriveStream {
+	return &deriveStream{key: key}
+}
+
+func (ds *deriveStream) byte() byte {
+	if len(ds.buf) == 0 {
+		mac := hmac.New(sha256.New, ds.key)
+		var block [8]byte
+		binary.BigEndian.PutUint64(block[:], ds.counter)
+		mac.Write(block[:])
+		ds.buf = mac.Sum(nil)
+		ds.counter++
+	}
+	b := ds.buf[0]
+	ds.buf = ds.buf[1:]
+	return b
+}
+
+// intn 拒绝采样返回[0,n)内的均匀随机数,避免取模偏差
+func (ds *deriveStream) intn(n int) int {
+	// 使用两个字节,字符集最多65536个字符
+	limit := 65536 - 65536%n
+	for {
+		v := int(ds.byte())<<8 | int(ds.byte())
+		if v < limit {
+			return v % n
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package gen
+
+import "testing"
+
+// deriveVectors 固定的派生结果,派生参数或字节流变化会导致已保存的密码全部失效,此时测试必须失败
+var deriveVectors = []struct {
+	name string
+	dc   DeriveConf
+	want string
+}{
+	{
+		name: "alphanumeric",
+		dc: DeriveConf{Master: "correct horse battery staple", Site: "example.com", Login: "alice", Counter: 1,
+			Conf: &PasswdGenConf{Length: 16, EnableNumber: true, EnableLowercase: true, EnableUppercase: true,
+				EnableDuplicate: true}},
+		want: "8H3Z4xUJD94Vz8Ip",
+	},
+	{
+		// 站点名称忽略大小写和首尾空白
+		name: "site normalized",
+		dc: DeriveConf{Master: "correct horse battery staple", Site: " Example.COM ", Login: "alice", Counter: 1,
+			Conf: &PasswdGenConf{Length: 16, EnableNumber: true, EnableLowercase: true, EnableUppercase: true,
+				EnableDuplicate: true}},
+		want: "8H3Z4xUJD94Vz8Ip",
+	},
+	{
+		name: "counter",
+		dc: DeriveConf{Master: "correct horse battery staple", Site: "example.com", Login: "alice", Counter: 2,
+			Conf: &PasswdGenConf{Length: 16, EnableNumber: true, EnableLowercase: true, EnableUppercase: true,
+				EnableDuplicate: true}},
+		want: "T4yzoPimFUpNmr12",
+	},
+	{
+		name: "empty login",
+		dc: DeriveConf{Master: "correct horse battery staple", Site: "example.com", Counter: 1,
+			Conf: &PasswdGenConf{Length: 16, EnableNumber: true, EnableLowercase: true, EnableUppercase: true,
+				EnableDuplicate: true}},
+		want: "hfE9NAvRf1xtWOOb",
+	},
+	{
+		name: "special characters",
+		dc: DeriveConf{Master: "Tr0ub4dor&3", Site: "mail.example.org", Login: "bob@example.org", Counter: 7,
+			Conf: &PasswdGenConf{Length: 24, EnableNumber: true, EnableLowercase: true, EnableUppercase: true,
+				EnableDuplicate: true, IncludeSpecialCharSet: "!@#$%^&*"}},
+		want: "zJ%dxkq5dfUXNo**sSMZ5yvh",
+	},
+	{
+		name: "no duplicate",
+		dc: DeriveConf{Master: "密码", Site: "例子.中国", Login: "用户", Counter: 1,
+			Conf: &PasswdGenConf{Length: 12, EnableNumber: true, EnableLowercase: true}},
+		want: "hrfkq3wl70mo",
+	},
+	{
+		name: "digits only",
+		dc: DeriveConf{Master: "1234", Site: "bank", Login: "card", Counter: 4294967295,
+			Conf: &PasswdGenConf{Length: 6, EnableNumber: true, EnableDuplicate: true}},
+		want: "557672",
+	},
+}
+
+func TestDerivePasswordVectors(t *testing.T) {
+	for _, tt := range deriveVectors {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := tt.dc
+			result, err := DerivePassword(&dc)
+			if err != nil {
+				t.Fatalf("DerivePassword failed: %v", err)
+			}
+			if result.Password != tt.want {
+				t.Errorf("DerivePassword = %q, want %q", result.Password, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package gen
+
+import "passwdgen/errcode"
+
+// ErrorCode 错误码,界面和命令行按错误码显示对应语言的提示
+type ErrorCode = errcode.Code
+
+const (
+	ErrorInvalidLength           ErrorCode = "invalid_length"
+	ErrorInvalidCharset          ErrorCode = "invalid_charset"
+	ErrorInvalidOptions          ErrorCode = "invalid_options"
+	ErrorBuildCharset            ErrorCode = "build_charset"
+	ErrorInvalidPolicy           ErrorCode = "invalid_policy"
+	ErrorPolicyUnsatisfied       ErrorCode = "policy_unsatisfied"
+	ErrorInvalidCharClass        ErrorCode = "invalid_char_class"
+	ErrorInvalidTokenFormat      ErrorCode = "invalid_token_format"
+	ErrorInvalidTokenBytes       ErrorCode = "invalid_token_bytes"
+	ErrorInvalidRecoveryCodeConf ErrorCode = "invalid_recovery_code_options"
+	ErrorRecoveryCodeSpace       ErrorCode = "recovery_code_space_too_small"
+	ErrorInvalidDeriveInput      ErrorCode = "invalid_derive_input"
+	ErrorDeriveUnsatisfied       ErrorCode = "derive_unsatisfied"
+	ErrorInvalidWifiLength       ErrorCode = "invalid_wifi_length"
+	ErrorInvalidWifiCharset      ErrorCode = "invalid_wifi_charset"
+	ErrorInvalidSSID             ErrorCode = "invalid_ssid"
+	ErrorInvalidPassphraseWords  ErrorCode = "invalid_passphrase_words"
+	ErrorInvalidPassphraseDigits ErrorCode = "invalid_passphrase_digits"
+)
+
+// 登记错误码对应的提示消息
+func init() {
+	errcode.Register(ErrorInvalidLength, "ErrorInvalidLengthMessage")
+	errcode.Register(ErrorInvalidCharset, "ErrorInvalidCharsetMessage")
+	errcode.Register(ErrorInvalidOptions, "ErrorInvalidOptionsMessage")
+	errcode.Register(ErrorBuildCharset, "ErrorBuildCharsetMessage")
+	errcode.Register(ErrorInvalidPolicy, "ErrorInvalidPolicyMessage")
+	errcode.Register(ErrorPolicyUnsatisfied, "ErrorPolicyUnsatisfiedMessage")
+	errcode.Register(ErrorInvalidCharClass, "ErrorInvalidCharClassMessage")
+	errcode.Register(ErrorInvalidTokenFormat, "ErrorInvalidTokenFormatMessage")
+	errcode.Register(ErrorInvalidTokenBytes, "ErrorInvalidTokenBytesMessage")
+	errcode.Register(ErrorInvalidRecoveryCodeConf, "ErrorInvalidRecoveryCodeConfMessage")
+	errcode.Register(ErrorRecoveryCodeSpace, "ErrorRecoveryCodeSpaceMessage")
+	errcode.Register(ErrorInvalidDeriveInput, "ErrorInvalidDeriveInputMessage")
+	errcode.Register(ErrorDeriveUnsatisfied, "ErrorDeriveUnsatisfiedMessage")
+	errcode.Register(ErrorInvalidWifiLength, "ErrorInvalidWifiLengthMessage")
+	errcode.Register(ErrorInvalidWifiCharset, "ErrorInvalidWifiCharsetMessage")
+	errcode.Register(ErrorInvalidSSID, "ErrorInvalidSSIDMessage")
+	errcode.Register(ErrorInvalidPassphraseWords, "ErrorInvalidPassphraseWordsMessage")
+	errcode.Register(ErrorInvalidPassphraseDigits, "ErrorInvalidPassphraseDigitsMessage")
+}
+
+// Error 带错误码的生成错误,显示文本由 i18n 按错误码提供
+type Error = errcode.Error
+
+func newError(code ErrorCode) *Error {
+	return errcode.New(code)
+}
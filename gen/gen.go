@@ -2,9 +2,9 @@ package gen
 
 import (
 	"crypto/rand"
-	"errors"
 	pv "github.com/wagslane/go-password-validator"
 	"image/color"
+	"math"
 	"math/big"
 	"strings"
 	"unicode/utf8"
@@ -18,10 +18,15 @@ const (
 	DefaultExcludeSpecialCharSet       = "iIl1o0O"
 )
 
-var invalidLengthError = errors.New("invalid length error (长度异常)")
-var invalidCharsetError = errors.New("invalid charset error (字符集异常)")
-var optionsError = errors.New("generation options error (选项异常)")
-var buildCharSetError = errors.New("generation build charset error (构建字符集异常)")
+var invalidLengthError = newError(ErrorInvalidLength)
+var invalidCharsetError = newError(ErrorInvalidCharset)
+var optionsError = newError(ErrorInvalidOptions)
+var buildCharSetError = newError(ErrorBuildCharset)
+var policyError = newError(ErrorInvalidPolicy)
+var policyUnsatisfiedError = newError(ErrorPolicyUnsatisfied)
+
+// MaxPolicyAttempts 按策略生成密码时的最大尝试次数
+const MaxPolicyAttempts = 10000
 
 func GeneratePassword(conf *PasswdGenConf) (*PasswdGenResult, error) {
 	if conf == nil {
@@ -30,7 +35,7 @@ func GeneratePassword(conf *PasswdGenConf) (*PasswdGenResult, error) {
 	if conf.Length <= 0 {
 		return nil, invalidLengthError
 	}
-	if !conf.EnableNumber && !conf.EnableLowercase && !conf.EnableUppercase && len(conf.IncludeSpecialCharSet) == 0 {
+	if !conf.hasCharSource() {
 		return nil, optionsError
 	}
 	charSet, err := buildCharSet(conf)
@@ -41,32 +46,120 @@ func GeneratePassword(conf *PasswdGenConf) (*PasswdGenResult, error) {
 	return internalPasswdGen(conf)
 }
 
+// PasswdPolicy 密码策略,要求每类字符至少出现的次数
+type PasswdPolicy struct {
+	MinNumber    int `toml:"min_number" json:"min_number"`
+	MinLowercase int `toml:"min_lowercase" json:"min_lowercase"`
+	MinUppercase int `toml:"min_uppercase" json:"min_uppercase"`
+	MinSpecial   int `toml:"min_special" json:"min_special"`
+}
+
+// GeneratePasswordWithPolicy 生成满足策略的密码,不满足时重新生成以保持字符分布均匀
+func GeneratePasswordWithPolicy(conf *PasswdGenConf, policy *PasswdPolicy) (*PasswdGenResult, error) {
+	if conf == nil {
+		conf = NewDefaultPasswdGenConf()
+	}
+	if policy == nil {
+		return GeneratePassword(conf)
+	}
+	if policy.MinNumber < 0 || policy.MinLowercase < 0 || policy.MinUppercase < 0 || policy.MinSpecial < 0 {
+		return nil, policyError
+	}
+	if policy.MinNumber+policy.MinLowercase+policy.MinUppercase+policy.MinSpecial > int(conf.Length) {
+		return nil, policyError
+	}
+	if !conf.hasCharSource() {
+		return nil, optionsError
+	}
+	charSet, err := buildCharSet(conf)
+	if err != nil {
+		return nil, err
+	}
+	// 字符集中没有某类字符时无法满足该类的最少数量,字符类包含的字母和数字按类别计入
+	available := make(map[CharClass]bool, 4)
+	for _, char := range charSet {
+		available[graphemeClass(char)] = true
+	}
+	if (policy.MinNumber > 0 && !available[CharClassDigit]) || (policy.MinLowercase > 0 && !available[CharClassLowercase]) ||
+		(policy.MinUppercase > 0 && !available[CharClassUppercase]) || (policy.MinSpecial > 0 && !available[CharClassSymbol]) {
+		return nil, policyError
+	}
+	for i := 0; i < MaxPolicyAttempts; i++ {
+		result, err := GeneratePassword(conf)
+		if err != nil {
+			return nil, err
+		}
+		if policy.satisfiedBy(result.Password) {
+			return result, nil
+		}
+	}
+	return nil, policyUnsatisfiedError
+}
+
+// satisfiedBy 按字符类别统计密码中的字符,例如 é 和 Ж 分别计为小写和大写字母,表情计为特殊字符
+func (p *PasswdPolicy) satisfiedBy(passwd string) bool {
+	counts := make(map[CharClass]int, 4)
+	for _, grapheme := range Graphemes(passwd) {
+		counts[graphemeClass(grapheme)]++
+	}
+	return counts[CharClassDigit] >= p.MinNumber && counts[CharClassLowercase] >= p.MinLowercase &&
+		counts[CharClassUppercase] >= p.MinUppercase && counts[CharClassSymbol] >= p.MinSpecial
+}
+
+// graphemeClass 返回字素簇的类别,按首个码点判断
+func graphemeClass(grapheme string) CharClass {
+	r, _ := utf8.DecodeRuneInString(grapheme)
+	return ClassOf(r)
+}
+
+// AnalyzePassword 计算已有密码的强度信息
+func AnalyzePassword(passwd string) *PasswdGenResult {
+	return newPasswdGenResult(passwd, pv.GetEntropy(passwd))
+}
+
+// charSetEntropy 从大小为size的字符集中随机选取length个字符的熵,不允许重复时逐个减少可选字符
+func charSetEntropy(size, length int, duplicate bool) float64 {
+	if duplicate {
+		return float64(length) * math.Log2(float64(size))
+	}
+	var entropy float64
+	for i := 0; i < length && size-i > 0; i++ {
+		entropy += math.Log2(float64(size - i))
+	}
+	return entropy
+}
+
+func newPasswdGenResult(passwd string, entropy float64) *PasswdGenResult {
+	strength := StrengthOf(entropy)
+	return &PasswdGenResult{
+		Password:      passwd,
+		StrengthInt:   entropy,
+		Strength:      strength,
+		StrengthColor: strength.Color(),
+		CostColor:     strength.CostColor(),
+	}
+}
+
 type PasswdGenResult struct {
-	Password      string
-	StrengthInt   float64
-	StrengthInfo  string
+	Password    string
+	StrengthInt float64
+	// 强度等级,显示文本和破解时间由 i18n 按等级提供
+	Strength      Strength
 	StrengthColor color.Color
-	CostInfo      string
 	CostColor     color.Color
 }
 
-type strengthInfo struct {
-	strengthInt   float64
-	strengthInfo  string
-	strengthColor color.Color
-	costInfo      string
-	costColor     color.Color
-}
-
 type PasswdGenConf struct {
 	// PUBLIC
-	Length                uint8
-	EnableNumber          bool
-	EnableLowercase       bool
-	EnableUppercase       bool
-	EnableDuplicate       bool
-	IncludeSpecialCharSet string
-	ExcludeSpecialCharSet string
+	Length                uint8  `toml:"length"`
+	EnableNumber          bool   `toml:"enable_number"`
+	EnableLowercase       bool   `toml:"enable_lowercase"`
+	EnableUppercase       bool   `toml:"enable_uppercase"`
+	EnableDuplicate       bool   `toml:"enable_duplicate"`
+	IncludeSpecialCharSet string `toml:"include_special_charset"`
+	ExcludeSpecialCharSet string `toml:"exclude_special_charset"`
+	// 启用的内置字符类,见 NamedCharClasses
+	Classes []string `toml:"classes"`
 	// PRIVATE
 	charSet []string
 }
@@ -83,7 +176,17 @@ func NewDefaultPasswdGenConf() *PasswdGenConf {
 	}
 }
 
+// hasCharSource 是否至少启用了一种字符来源
+func (conf *PasswdGenConf) hasCharSource() bool {
+	return conf.EnableNumber || conf.EnableLowercase || conf.EnableUppercase || len(conf.IncludeSpecialCharSet) > 0 ||
+		len(conf.Classes) > 0
+}
+
+// buildCharSet 按字素簇构建字符集,多字节字符(包括组合字符和表情)作为一个字符处理
 func buildCharSet(conf *PasswdGenConf) ([]string, error) {
+	if !utf8.ValidString(conf.IncludeSpecialCharSet) || !utf8.ValidString(conf.ExcludeSpecialCharSet) {
+		return nil, buildCharSetError
+	}
 	var charSet string
 	if conf.EnableNumber {
 		charSet += DefaultNumberCharSet
@@ -94,23 +197,23 @@ func buildCharSet(conf *PasswdGenConf) ([]string, error) {
 	if conf.EnableUppercase {
 		charSet += strings.ToUpper(DefaultLowercaseCharSet)
 	}
-	lis := len(conf.IncludeSpecialCharSet)
-	if lis > 0 {
-		if lis != utf8.RuneCountInString(conf.IncludeSpecialCharSet) {
-			return nil, buildCharSetError
+	split := Graphemes(charSet)
+	split = append(split, Graphemes(conf.IncludeSpecialCharSet)...)
+	for _, name := range conf.Classes {
+		chars, err := NamedCharClass(name)
+		if err != nil {
+			return nil, err
 		}
-		charSet += conf.IncludeSpecialCharSet
+		split = append(split, Graphemes(chars)...)
 	}
-	les := len(conf.ExcludeSpecialCharSet)
-	if les > 0 {
-		if les != utf8.RuneCountInString(conf.ExcludeSpecialCharSet) {
-			return nil, buildCharSetError
-		}
-		charSet = removeCharsetFor(charSet, conf.ExcludeSpecialCharSet)
+	if len(conf.ExcludeSpecialCharSet) > 0 {
+		split = removeCharsetFor(split, Graphemes(conf.ExcludeSpecialCharSet))
 	}
-	split := strings.Split(charSet, "")
 	// 移除字符集中的重复字符
 	split = removeDuplicateChars(split)
+	if len(split) == 0 {
+		return nil, invalidCharsetError
+	}
 	if !conf.EnableDuplicate {
 		// 如果不允许重复字符并且要求生成的长度大于现存字符集长度抛出错误
 		if int(conf.Length) > len(split) {
@@ -120,13 +223,18 @@ func buildCharSet(conf *PasswdGenConf) ([]string, error) {
 	return split, nil
 }
 
-func removeCharsetFor(input, charset string) string {
-	return strings.Map(func(r rune) rune {
-		if !strings.ContainsRune(charset, r) {
-			return r
+func removeCharsetFor(input, charset []string) []string {
+	excluded := make(map[string]struct{}, len(charset))
+	for _, c := range charset {
+		excluded[c] = struct{}{}
+	}
+	r := make([]string, 0, len(input))
+	for _, c := range input {
+		if _, ok := excluded[c]; !ok {
+			r = append(r, c)
 		}
-		return -1
-	}, input)
+	}
+	return r
 }
 
 func removeDuplicateChars(sl []string) []string {
@@ -154,86 +262,38 @@ func internalPasswdGen(conf *PasswdGenConf) (*PasswdGenResult, error) {
 			result += charsetToUse[index.Int64()]
 		}
 	} else {
+		// 按抽取顺序拼接,保证字符的排列也是随机的
 		m := make(map[string]struct{}, 0)
 		for uint8(len(m)) < conf.Length {
 			index, err := rand.Int(rand.Reader, max)
 			if err != nil {
 				return nil, err
 			}
-			m[charsetToUse[index.Int64()]] = struct{}{}
-		}
-		for k := range m {
-			result += k
+			c := charsetToUse[index.Int64()]
+			if _, ok := m[c]; ok {
+				continue
+			}
+			m[c] = struct{}{}
+			result += c
 		}
 	}
-	csi := generateStrengthInfo(result)
-	return &PasswdGenResult{
-		Password:      result,
-		StrengthInt:   csi.strengthInt,
-		StrengthInfo:  csi.strengthInfo,
-		StrengthColor: csi.strengthColor,
-		CostInfo:      csi.costInfo,
-		CostColor:     csi.costColor,
-	}, nil
-}
-
-func generateStrengthInfo(passwd string) *strengthInfo {
-	entropy := pv.GetEntropy(passwd)
-	// ColorOrange
-	cc := color.NRGBA{R: 0xff, G: 0x98, B: 0x00, A: 0xff}
+	return newPasswdGenResult(result, charSetEntropy(len(charsetToUse), int(conf.Length), conf.EnableDuplicate)), nil
+}
+
+// StrengthOf 按熵(bit)计算强度等级
+func StrengthOf(entropy float64) Strength {
 	switch {
 	case entropy < 20:
-		return &strengthInfo{
-			strengthInt:  entropy,
-			strengthInfo: "VERY WEAK",
-			// ColorRed
-			strengthColor: color.NRGBA{R: 0xf4, G: 0x43, B: 0x36, A: 0xff},
-			costInfo:      "(>>> 0 s)",
-			costColor:     cc,
-		}
+		return StrengthVeryWeak
 	case entropy < 40:
-		return &strengthInfo{
-			strengthInt:  entropy,
-			strengthInfo: "WEAK",
-			// ColorOrange
-			strengthColor: color.NRGBA{R: 0xff, G: 0x98, B: 0x00, A: 0xff},
-			costInfo:      "(>>> 0 s ~ 3.5 y)",
-			costColor:     cc,
-		}
+		return StrengthWeak
 	case entropy < 60:
-		return &strengthInfo{
-			strengthInt:  entropy,
-			strengthInfo: "NORMAL",
-			// ColorYellow
-			strengthColor: color.NRGBA{R: 0xff, G: 0xeb, B: 0x3b, A: 0xff},
-			costInfo:      "(>>> 0 s ~ 913 mi)",
-			costColor:     cc,
-		}
+		return StrengthNormal
 	case entropy < 80:
-		return &strengthInfo{
-			strengthInt:  entropy,
-			strengthInfo: "STRONG",
-			// ColorBlue
-			strengthColor: color.NRGBA{R: 0x29, G: 0x6f, B: 0xf6, A: 0xff},
-			costInfo:      "(3.2 h ~ 958 by)",
-			costColor:     cc,
-		}
+		return StrengthStrong
 	case entropy >= 80:
-		return &strengthInfo{
-			strengthInt:  entropy,
-			strengthInfo: "VERY STRONG",
-			// ColorGreen
-			strengthColor: color.NRGBA{R: 0x8b, G: 0xc3, B: 0x4a, A: 0xff},
-			costInfo:      "(383 y ~ +Infinity)",
-			costColor:     cc,
-		}
+		return StrengthVeryStrong
 	default:
-		return &strengthInfo{
-			strengthInt:   entropy,
-			strengthInfo:  "UNKNOWN",
-			strengthColor: nil,
-			costInfo:      "",
-			costColor:     nil,
-		}
+		return StrengthUnknown
 	}
 }
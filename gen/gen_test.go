@@ -0,0 +1,86 @@
+package gen
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPolicySatisfiedBy(t *testing.T) {
+	policy := &PasswdPolicy{MinNumber: 1, MinLowercase: 2, MinUppercase: 2, MinSpecial: 1}
+	tests := []struct {
+		passwd string
+		want   bool
+	}{
+		{"aB3!cD", true},
+		// 字符类中的字母按大小写计入,而不是计为特殊字符
+		{"éжΣÀ7!", true},
+		{"éж7!", false},
+		{"ÀΣ7!ab", true},
+		// 表情计为特殊字符,带修饰符的表情只算一个字符
+		{"abCD7😀", true},
+		{"abCD7👍🏽", true},
+		{"abCD7", false},
+		// 全角数字计为数字
+		{"abCD!٣", true},
+	}
+	for _, tt := range tests {
+		if got := policy.satisfiedBy(tt.passwd); got != tt.want {
+			t.Errorf("satisfiedBy(%q) = %v, want %v", tt.passwd, got, tt.want)
+		}
+	}
+}
+
+func TestGeneratePasswordWithPolicyClasses(t *testing.T) {
+	tests := []struct {
+		name   string
+		conf   *PasswdGenConf
+		policy *PasswdPolicy
+		err    error
+	}{
+		{
+			name:   "cyrillic provides both cases",
+			conf:   &PasswdGenConf{Length: 12, EnableDuplicate: true, Classes: []string{ClassNameCyrillic}},
+			policy: &PasswdPolicy{MinLowercase: 3, MinUppercase: 3},
+		},
+		{
+			name:   "emoji provides specials",
+			conf:   &PasswdGenConf{Length: 12, EnableLowercase: true, EnableDuplicate: true, Classes: []string{ClassNameEmoji}},
+			policy: &PasswdPolicy{MinLowercase: 2, MinSpecial: 2},
+		},
+		{
+			name:   "hex has no uppercase",
+			conf:   &PasswdGenConf{Length: 12, EnableDuplicate: true, Classes: []string{ClassNameHex}},
+			policy: &PasswdPolicy{MinUppercase: 1},
+			err:    policyError,
+		},
+		{
+			name:   "excluded digits",
+			conf:   &PasswdGenConf{Length: 12, EnableNumber: true, EnableLowercase: true, EnableDuplicate: true, ExcludeSpecialCharSet: "0123456789"},
+			policy: &PasswdPolicy{MinNumber: 1},
+			err:    policyError,
+		},
+		{
+			name:   "no specials",
+			conf:   &PasswdGenConf{Length: 12, EnableLowercase: true, EnableDuplicate: true, Classes: []string{ClassNameLatin1}},
+			policy: &PasswdPolicy{MinSpecial: 1},
+			err:    policyError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GeneratePasswordWithPolicy(tt.conf, tt.policy)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if !tt.policy.satisfiedBy(result.Password) {
+				t.Errorf("password %q does not satisfy %+v", result.Password, tt.policy)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package gen
+
+import (
+	"crypto/rand"
+	"math"
+	"math/big"
+	"passwdgen/wordlist"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	DefaultPassphraseWords     = 6
+	MinPassphraseWords         = 3
+	MaxPassphraseWords         = 20
+	MaxPassphraseDigits        = 8
+	DefaultPassphraseSeparator = "-"
+)
+
+var invalidPassphraseWordsError = newError(ErrorInvalidPassphraseWords)
+var invalidPassphraseDigitsError = newError(ErrorInvalidPassphraseDigits)
+
+// PassphraseConf 由词表中随机单词组成的口令配置
+type PassphraseConf struct {
+	Words int `toml:"words"`
+	// 词表名称,为空时使用英文词表
+	WordList  string `toml:"word_list"`
+	Separator string `toml:"separator"`
+	// 单词首字母大写,不增加熵
+	Capitalize bool `toml:"capitalize"`
+	// 末尾追加的随机数字位数
+	Digits int `toml:"digits"`
+}
+
+func NewDefaultPassphraseConf() *PassphraseConf {
+	return &PassphraseConf{
+		Words:     DefaultPassphraseWords,
+		Separator: DefaultPassphraseSeparator,
+	}
+}
+
+// GeneratePassphrase 从词表中随机选取单词组成口令,熵按词表大小计算
+func GeneratePassphrase(conf *PassphraseConf) (*PasswdGenResult, error) {
+	if conf == nil {
+		conf = NewDefaultPassphraseConf()
+	}
+	if conf.Words < MinPassphraseWords || conf.Words > MaxPassphraseWords {
+		return nil, invalidPassphraseWordsError
+	}
+	if conf.Digits < 0 || conf.Digits > MaxPassphraseDigits {
+		return nil, invalidPassphraseDigitsError
+	}
+	name := conf.WordList
+	if name == "" {
+		name = wordlist.English
+	}
+	list, err := wordlist.Default().Get(name)
+	if err != nil {
+		return nil, err
+	}
+	words := list.Words
+	parts := make([]string, 0, conf.Words+1)
+	for i := 0; i < conf.Words; i++ {
+		word, err := randomElement(words)
+		if err != nil {
+			return nil, err
+		}
+		if conf.Capitalize {
+			word = capitalize(word)
+		}
+		parts = append(parts, word)
+	}
+	entropy := float64(conf.Words) * list.Entropy()
+	if conf.Digits > 0 {
+		var digits strings.Builder
+		for i := 0; i < conf.Digits; i++ {
+			digit, err := randomElement(Graphemes(DefaultNumberCharSet))
+			if err != nil {
+				return nil, err
+			}
+			digits.WriteString(digit)
+		}
+		parts = append(parts, digits.String())
+		entropy += float64(conf.Digits) * math.Log2(float64(len(DefaultNumberCharSet)))
+	}
+	return newPasswdGenResult(strings.Join(parts, conf.Separator), entropy), nil
+}
+
+func randomElement(elements []string) (string, error) {
+	index, err := rand.Int(rand.Reader, big.NewInt(int64(len(elements))))
+	if err != nil {
+		return "", err
+	}
+	return elements[index.Int64()], nil
+}
+
+func capitalize(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToUpper(r)) + word[size:]
+}
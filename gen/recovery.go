@@ -0,0 +1,106 @@
+package gen
+
+import (
+	"crypto/rand"
+	"math"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	DefaultRecoveryCodeCount     = 10
+	DefaultRecoveryCodeGroups    = 2
+	DefaultRecoveryCodeGroupSize = 4
+	DefaultRecoveryCodeSeparator = "-"
+	MaxRecoveryCodeCount         = 1000
+	MaxRecoveryCodeLength        = 64
+)
+
+var invalidRecoveryCodeConfError = newError(ErrorInvalidRecoveryCodeConf)
+var recoveryCodeSpaceError = newError(ErrorRecoveryCodeSpace)
+
+// RecoveryCodeConf 恢复码配置,例如10个 xxxx-xxxx 格式的恢复码
+type RecoveryCodeConf struct {
+	Count     int    `toml:"count"`
+	Groups    int    `toml:"groups"`
+	GroupSize int    `toml:"group_size"`
+	Separator string `toml:"separator"`
+	// 为空时使用去除易混淆字符后的数字和小写字母
+	Alphabet string `toml:"alphabet"`
+}
+
+type RecoveryCodeResult struct {
+	Codes []string
+	// 每个恢复码的熵(bit)
+	Entropy float64
+}
+
+func NewDefaultRecoveryCodeConf() *RecoveryCodeConf {
+	return &RecoveryCodeConf{
+		Count:     DefaultRecoveryCodeCount,
+		Groups:    DefaultRecoveryCodeGroups,
+		GroupSize: DefaultRecoveryCodeGroupSize,
+		Separator: DefaultRecoveryCodeSeparator,
+	}
+}
+
+// DefaultRecoveryCodeAlphabet 数字和小写字母去除 DefaultExcludeSpecialCharSet 中的易混淆字符
+func DefaultRecoveryCodeAlphabet() string {
+	charSet, _ := buildCharSet(&PasswdGenConf{
+		EnableNumber:          true,
+		EnableLowercase:       true,
+		ExcludeSpecialCharSet: DefaultExcludeSpecialCharSet,
+	})
+	return strings.Join(charSet, "")
+}
+
+// GenerateRecoveryCodes 生成一组互不重复的恢复码
+func GenerateRecoveryCodes(conf *RecoveryCodeConf) (*RecoveryCodeResult, error) {
+	if conf == nil {
+		conf = NewDefaultRecoveryCodeConf()
+	}
+	if conf.Count <= 0 || conf.Count > MaxRecoveryCodeCount || conf.Groups <= 0 || conf.GroupSize <= 0 ||
+		conf.Groups*conf.GroupSize > MaxRecoveryCodeLength {
+		return nil, invalidRecoveryCodeConfError
+	}
+	alphabet := conf.Alphabet
+	if alphabet == "" {
+		alphabet = DefaultRecoveryCodeAlphabet()
+	}
+	charSet := removeDuplicateChars(Graphemes(alphabet))
+	if !utf8.ValidString(alphabet) || len(charSet) < 2 ||
+		strings.ContainsAny(conf.Separator, alphabet) {
+		return nil, invalidRecoveryCodeConfError
+	}
+	length := conf.Groups * conf.GroupSize
+	entropy := float64(length) * math.Log2(float64(len(charSet)))
+	// 可能的恢复码数量必须远大于需要的数量,避免反复重试
+	if entropy < math.Log2(float64(conf.Count))+1 {
+		return nil, recoveryCodeSpaceError
+	}
+	max := big.NewInt(int64(len(charSet)))
+	seen := make(map[string]struct{}, conf.Count)
+	codes := make([]string, 0, conf.Count)
+	for len(codes) < conf.Count {
+		groups := make([]string, conf.Groups)
+		for g := range groups {
+			var group strings.Builder
+			for i := 0; i < conf.GroupSize; i++ {
+				index, err := rand.Int(rand.Reader, max)
+				if err != nil {
+					return nil, err
+				}
+				group.WriteString(charSet[index.Int64()])
+			}
+			groups[g] = group.String()
+		}
+		code := strings.Join(groups, conf.Separator)
+		if _, ok := seen[code]; ok {
+			continue
+		}
+		seen[code] = struct{}{}
+		codes = append(codes, code)
+	}
+	return &RecoveryCodeResult{Codes: codes, Entropy: entropy}, nil
+}
@@ -0,0 +1,70 @@
+package gen
+
+import "image/color"
+
+// Strength 密码强度等级
+type Strength int
+
+const (
+	StrengthUnknown Strength = iota
+	StrengthVeryWeak
+	StrengthWeak
+	StrengthNormal
+	StrengthStrong
+	StrengthVeryStrong
+)
+
+// Strengths 所有强度等级
+var Strengths = []Strength{
+	StrengthUnknown,
+	StrengthVeryWeak,
+	StrengthWeak,
+	StrengthNormal,
+	StrengthStrong,
+	StrengthVeryStrong,
+}
+
+// strengthNames 强度等级的名称,界面和命令行按名称显示对应语言的文本
+var strengthNames = map[Strength]string{
+	StrengthUnknown:    "unknown",
+	StrengthVeryWeak:   "very_weak",
+	StrengthWeak:       "weak",
+	StrengthNormal:     "normal",
+	StrengthStrong:     "strong",
+	StrengthVeryStrong: "very_strong",
+}
+
+// String 强度等级的名称,例如 very_weak
+func (s Strength) String() string {
+	if name, ok := strengthNames[s]; ok {
+		return name
+	}
+	return strengthNames[StrengthUnknown]
+}
+
+var strengthColors = map[Strength]color.Color{
+	// ColorRed
+	StrengthVeryWeak: color.NRGBA{R: 0xf4, G: 0x43, B: 0x36, A: 0xff},
+	// ColorOrange
+	StrengthWeak: color.NRGBA{R: 0xff, G: 0x98, B: 0x00, A: 0xff},
+	// ColorYellow
+	StrengthNormal: color.NRGBA{R: 0xff, G: 0xeb, B: 0x3b, A: 0xff},
+	// ColorBlue
+	StrengthStrong: color.NRGBA{R: 0x29, G: 0x6f, B: 0xf6, A: 0xff},
+	// ColorGreen
+	StrengthVeryStrong: color.NRGBA{R: 0x8b, G: 0xc3, B: 0x4a, A: 0xff},
+}
+
+// Color 强度等级的显示颜色,未知等级返回nil
+func (s Strength) Color() color.Color {
+	return strengthColors[s]
+}
+
+// CostColor 破解时间的显示颜色
+func (s Strength) CostColor() color.Color {
+	if s == StrengthUnknown {
+		return nil
+	}
+	// ColorOrange
+	return color.NRGBA{R: 0xff, G: 0x98, B: 0x00, A: 0xff}
+}
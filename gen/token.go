@@ -0,0 +1,219 @@
+package gen
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"math/big"
+	"strings"
+	"time"
+)
+
+type TokenFormat string
+
+const (
+	TokenFormatHex             TokenFormat = "hex"
+	TokenFormatBase32          TokenFormat = "base32"
+	TokenFormatBase32Crockford TokenFormat = "base32-crockford"
+	TokenFormatBase58          TokenFormat = "base58"
+	TokenFormatBase64URL       TokenFormat = "base64url"
+	TokenFormatUUIDv4          TokenFormat = "uuid"
+	TokenFormatULID            TokenFormat = "ulid"
+)
+
+const (
+	DefaultTokenBytes = 32
+	MaxTokenBytes     = 1024
+	// TokenChecksumLength 校验和长度,CRC32按base62编码后补齐6位
+	TokenChecksumLength = 6
+	Base58Alphabet      = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	CrockfordAlphabet   = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	base62Alphabet      = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+var TokenFormats = []TokenFormat{
+	TokenFormatHex,
+	TokenFormatBase32,
+	TokenFormatBase32Crockford,
+	TokenFormatBase58,
+	TokenFormatBase64URL,
+	TokenFormatUUIDv4,
+	TokenFormatULID,
+}
+
+var invalidTokenFormatError = newError(ErrorInvalidTokenFormat)
+var invalidTokenBytesError = newError(ErrorInvalidTokenBytes)
+
+var crockfordEncoding = base32.NewEncoding(CrockfordAlphabet).WithPadding(base32.NoPadding)
+
+type TokenGenConf struct {
+	Format TokenFormat `toml:"format"`
+	// 随机字节数,UUID和ULID忽略该值
+	Bytes int `toml:"bytes"`
+	// 令牌前缀,例如 sk_live_
+	Prefix string `toml:"prefix"`
+	// 是否追加CRC32校验和,便于扫描工具识别泄露的令牌
+	Checksum bool `toml:"checksum"`
+}
+
+type TokenGenResult struct {
+	Token string
+	// 随机部分的熵(bit)
+	Entropy float64
+}
+
+func NewDefaultTokenGenConf() *TokenGenConf {
+	return &TokenGenConf{
+		Format: TokenFormatHex,
+		Bytes:  DefaultTokenBytes,
+	}
+}
+
+// ParseTokenFormat 解析令牌格式名称
+func ParseTokenFormat(format string) (TokenFormat, error) {
+	for _, tf := range TokenFormats {
+		if string(tf) == strings.ToLower(format) {
+			return tf, nil
+		}
+	}
+	return "", invalidTokenFormatError
+}
+
+func GenerateToken(conf *TokenGenConf) (*TokenGenResult, error) {
+	if conf == nil {
+		conf = NewDefaultTokenGenConf()
+	}
+	var body string
+	var entropy float64
+	switch conf.Format {
+	case TokenFormatUUIDv4:
+		b, err := randomBytes(16)
+		if err != nil {
+			return nil, err
+		}
+		body, entropy = formatUUIDv4(b), 122
+	case TokenFormatULID:
+		b, err := randomBytes(10)
+		if err != nil {
+			return nil, err
+		}
+		body, entropy = formatULID(time.Now(), b), 80
+	case TokenFormatHex, TokenFormatBase32, TokenFormatBase32Crockford, TokenFormatBase58, TokenFormatBase64URL:
+		if conf.Bytes <= 0 || conf.Bytes > MaxTokenBytes {
+			return nil, invalidTokenBytesError
+		}
+		b, err := randomBytes(conf.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		body, entropy = encodeToken(conf.Format, b), float64(conf.Bytes*8)
+	default:
+		return nil, invalidTokenFormatError
+	}
+	token := conf.Prefix + body
+	if conf.Checksum {
+		token += TokenChecksum(token)
+	}
+	return &TokenGenResult{Token: token, Entropy: entropy}, nil
+}
+
+// TokenChecksum 计算令牌的CRC32校验和,按base62编码为6位
+func TokenChecksum(token string) string {
+	sum := crc32.ChecksumIEEE([]byte(token))
+	encoded := encodeBase(new(big.Int).SetUint64(uint64(sum)), base62Alphabet)
+	return strings.Repeat("0", TokenChecksumLength-len(encoded)) + encoded
+}
+
+// VerifyTokenChecksum 校验令牌末尾的校验和
+func VerifyTokenChecksum(token string) bool {
+	if len(token) <= TokenChecksumLength {
+		return false
+	}
+	body, sum := token[:len(token)-TokenChecksumLength], token[len(token)-TokenChecksumLength:]
+	return TokenChecksum(body) == sum
+}
+
+func encodeToken(format TokenFormat, b []byte) string {
+	switch format {
+	case TokenFormatHex:
+		return hex.EncodeToString(b)
+	case TokenFormatBase32:
+		return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b)
+	case TokenFormatBase32Crockford:
+		return crockfordEncoding.EncodeToString(b)
+	case TokenFormatBase58:
+		return encodeBase58(b)
+	case TokenFormatBase64URL:
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	return ""
+}
+
+// encodeBase58 Bitcoin字母表的base58编码,前导零字节编码为1
+func encodeBase58(b []byte) string {
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+	encoded := ""
+	if zeros < len(b) {
+		encoded = encodeBase(new(big.Int).SetBytes(b), Base58Alphabet)
+	}
+	return strings.Repeat(string(Base58Alphabet[0]), zeros) + encoded
+}
+
+func encodeBase(n *big.Int, alphabet string) string {
+	if n.Sign() == 0 {
+		return string(alphabet[0])
+	}
+	base := big.NewInt(int64(len(alphabet)))
+	mod := new(big.Int)
+	n = new(big.Int).Set(n)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, base, mod)
+		out = append(out, alphabet[mod.Int64()])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+func formatUUIDv4(b []byte) string {
+	// 版本4,RFC 4122变体
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// formatULID 48位毫秒时间戳加80位随机数,Crockford base32编码为26位
+func formatULID(t time.Time, entropy []byte) string {
+	var b [16]byte
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(t.UnixMilli()))
+	copy(b[:6], ms[2:])
+	copy(b[6:], entropy)
+	// 128位按5位一组编码,首位只有3位
+	n := new(big.Int).SetBytes(b[:])
+	out := make([]byte, 26)
+	mask := big.NewInt(31)
+	tmp := new(big.Int)
+	for i := 25; i >= 0; i-- {
+		out[i] = CrockfordAlphabet[tmp.And(n, mask).Int64()]
+		n.Rsh(n, 5)
+	}
+	return string(out)
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,107 @@
+package gen
+
+import (
+	"strings"
+)
+
+const (
+	// WPA预共享密钥的口令为8到63个可打印ASCII字符
+	WifiMinLength     uint8 = 8
+	WifiMaxLength     uint8 = 63
+	WifiDefaultLength uint8 = 20
+	WifiMaxSSIDLength       = 32
+	WifiSecurityWPA         = "WPA"
+)
+
+var invalidWifiLengthError = newError(ErrorInvalidWifiLength)
+var invalidWifiCharsetError = newError(ErrorInvalidWifiCharset)
+var invalidSSIDError = newError(ErrorInvalidSSID)
+
+// WifiConf Wi-Fi凭据配置
+type WifiConf struct {
+	SSID   string `toml:"ssid"`
+	Hidden bool   `toml:"hidden"`
+	// 口令使用的字符集,长度须在WPA允许的范围内
+	Conf *PasswdGenConf `toml:"conf"`
+}
+
+type WifiResult struct {
+	*PasswdGenResult
+	// 标准的 WIFI:T:WPA;S:ssid;P:pass;; 二维码内容
+	Payload string
+}
+
+// NewDefaultWifiConf 返回默认的Wi-Fi配置
+func NewDefaultWifiConf() *WifiConf {
+	conf := NewDefaultPasswdGenConf()
+	conf.Length = WifiDefaultLength
+	return &WifiConf{Conf: conf}
+}
+
+// GenerateWifiCredential 生成符合WPA规则的口令及二维码内容
+func GenerateWifiCredential(wc *WifiConf) (*WifiResult, error) {
+	if wc == nil {
+		wc = NewDefaultWifiConf()
+	}
+	if wc.SSID == "" || len(wc.SSID) > WifiMaxSSIDLength {
+		return nil, invalidSSIDError
+	}
+	conf := wc.Conf
+	if conf == nil {
+		conf = NewDefaultWifiConf().Conf
+	}
+	if conf.Length < WifiMinLength || conf.Length > WifiMaxLength {
+		return nil, invalidWifiLengthError
+	}
+	if !isPrintableASCII(conf.IncludeSpecialCharSet) {
+		return nil, invalidWifiCharsetError
+	}
+	for _, name := range conf.Classes {
+		if chars, err := NamedCharClass(name); err != nil || !isPrintableASCII(chars) {
+			return nil, invalidWifiCharsetError
+		}
+	}
+	result, err := GeneratePassword(conf)
+	if err != nil {
+		return nil, err
+	}
+	return &WifiResult{
+		PasswdGenResult: result,
+		Payload:         WifiPayload(wc.SSID, result.Password, wc.Hidden),
+	}, nil
+}
+
+// WifiPayload 构建Wi-Fi二维码内容,特殊字符 \ ; , : " 使用反斜杠转义
+func WifiPayload(ssid, passphrase string, hidden bool) string {
+	var b strings.Builder
+	b.WriteString("WIFI:T:" + WifiSecurityWPA + ";S:")
+	b.WriteString(escapeWifi(ssid))
+	b.WriteString(";P:")
+	b.WriteString(escapeWifi(passphrase))
+	b.WriteString(";")
+	if hidden {
+		b.WriteString("H:true;")
+	}
+	b.WriteString(";")
+	return b.String()
+}
+
+func escapeWifi(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`\;,:"`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func isPrintableASCII(s string) bool {
+	for _, r := range s {
+		if r < 0x20 || r > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,281 @@
+package hasher
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"io"
+	"passwdgen/errcode"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatBcrypt       Format = "bcrypt"
+	FormatArgon2id     Format = "argon2id"
+	FormatScrypt       Format = "scrypt"
+	FormatPBKDF2SHA256 Format = "pbkdf2-sha256"
+	FormatSHA512Crypt  Format = "sha512-crypt"
+	FormatHtpasswd     Format = "htpasswd"
+	FormatScramSHA256  Format = "scram-sha-256"
+)
+
+// Formats 所有支持的哈希格式
+var Formats = []Format{
+	FormatBcrypt,
+	FormatArgon2id,
+	FormatScrypt,
+	FormatPBKDF2SHA256,
+	FormatSHA512Crypt,
+	FormatHtpasswd,
+	FormatScramSHA256,
+}
+
+const saltLength = 16
+
+// randReader 盐的随机数来源,测试时替换为固定的盐
+var randReader io.Reader = rand.Reader
+
+const (
+	ErrorInvalidFormat errcode.Code = "invalid_hash_format"
+	ErrorInvalidParams errcode.Code = "invalid_hash_params"
+)
+
+// 登记错误码对应的提示消息
+func init() {
+	errcode.Register(ErrorInvalidFormat, "ErrorInvalidHashFormatMessage")
+	errcode.Register(ErrorInvalidParams, "ErrorInvalidHashParamsMessage")
+}
+
+var invalidFormatError = errcode.New(ErrorInvalidFormat)
+var invalidParamsError = errcode.New(ErrorInvalidParams)
+
+// Params 各哈希算法的代价参数
+type Params struct {
+	BcryptCost int `toml:"bcrypt_cost" json:"bcrypt_cost"`
+	// Argon2id迭代次数、内存(KiB)和并行度
+	Argon2Time    uint32 `toml:"argon2_time" json:"argon2_time"`
+	Argon2Memory  uint32 `toml:"argon2_memory" json:"argon2_memory"`
+	Argon2Threads uint8  `toml:"argon2_threads" json:"argon2_threads"`
+	// scrypt的N取以2为底的对数
+	ScryptLogN        int `toml:"scrypt_ln" json:"scrypt_ln"`
+	ScryptR           int `toml:"scrypt_r" json:"scrypt_r"`
+	ScryptP           int `toml:"scrypt_p" json:"scrypt_p"`
+	PBKDF2Iterations  int `toml:"pbkdf2_iterations" json:"pbkdf2_iterations"`
+	SHA512CryptRounds int `toml:"sha512_crypt_rounds" json:"sha512_crypt_rounds"`
+	ScramIterations   int `toml:"scram_iterations" json:"scram_iterations"`
+}
+
+// NewDefaultParams 返回推荐的默认代价参数
+func NewDefaultParams() *Params {
+	return &Params{
+		BcryptCost:        12,
+		Argon2Time:        3,
+		Argon2Memory:      64 * 1024,
+		Argon2Threads:     4,
+		ScryptLogN:        17,
+		ScryptR:           8,
+		ScryptP:           1,
+		PBKDF2Iterations:  600000,
+		SHA512CryptRounds: 5000,
+		ScramIterations:   4096,
+	}
+}
+
+// Validate 检查各个格式的代价参数,参数无效时返回 invalidParamsError
+func (p *Params) Validate() error {
+	for _, format := range Formats {
+		if !p.valid(format) {
+			return fmt.Errorf("%s: %w", format, invalidParamsError)
+		}
+	}
+	return nil
+}
+
+// valid 检查格式使用的代价参数
+func (p *Params) valid(format Format) bool {
+	switch format {
+	case FormatBcrypt, FormatHtpasswd:
+		return p.BcryptCost >= bcrypt.MinCost && p.BcryptCost <= bcrypt.MaxCost
+	case FormatArgon2id:
+		return p.Argon2Time >= 1 && p.Argon2Threads >= 1 && p.Argon2Memory >= 8*uint32(p.Argon2Threads)
+	case FormatScrypt:
+		return p.ScryptLogN >= 1 && p.ScryptLogN <= 30 && p.ScryptR >= 1 && p.ScryptP >= 1
+	case FormatPBKDF2SHA256:
+		return p.PBKDF2Iterations >= 1
+	case FormatSHA512Crypt:
+		return p.SHA512CryptRounds >= sha512CryptMinRounds && p.SHA512CryptRounds <= sha512CryptMaxRounds
+	case FormatScramSHA256:
+		return p.ScramIterations >= 1
+	}
+	return false
+}
+
+// Result 单个格式的哈希结果
+type Result struct {
+	Format Format
+	Hash   string
+}
+
+// ParseFormat 解析哈希格式名称
+func ParseFormat(format string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(strings.TrimSpace(format)) {
+			return f, nil
+		}
+	}
+	return "", invalidFormatError
+}
+
+// ParseFormats 解析逗号分隔的哈希格式列表,all表示全部
+func ParseFormats(formats string) ([]Format, error) {
+	if strings.TrimSpace(formats) == "all" {
+		return Formats, nil
+	}
+	var result []Format
+	for _, name := range strings.Split(formats, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		f, err := ParseFormat(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		result = append(result, f)
+	}
+	return result, nil
+}
+
+// HashAll 按顺序计算多个格式的哈希
+func HashAll(password string, formats []Format, params *Params) ([]*Result, error) {
+	results := make([]*Result, 0, len(formats))
+	for _, format := range formats {
+		h, err := Hash(format, password, params)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", format, err)
+		}
+		results = append(results, &Result{Format: format, Hash: h})
+	}
+	return results, nil
+}
+
+// Hash 计算密码在指定格式下的哈希
+func Hash(format Format, password string, params *Params) (string, error) {
+	if params == nil {
+		params = NewDefaultParams()
+	}
+	if !params.valid(format) {
+		// 未知格式的参数同样无效
+		for _, f := range Formats {
+			if f == format {
+				return "", invalidParamsError
+			}
+		}
+		return "", invalidFormatError
+	}
+	switch format {
+	case FormatBcrypt:
+		return hashBcrypt(password, params)
+	case FormatHtpasswd:
+		// htpasswd -B 使用 $2y$ 前缀
+		h, err := hashBcrypt(password, params)
+		if err != nil {
+			return "", err
+		}
+		return "$2y$" + strings.TrimPrefix(h, "$2a$"), nil
+	case FormatArgon2id:
+		return hashArgon2id(password, params)
+	case FormatScrypt:
+		return hashScrypt(password, params)
+	case FormatPBKDF2SHA256:
+		return hashPBKDF2SHA256(password, params)
+	case FormatSHA512Crypt:
+		return hashSHA512Crypt(password, params)
+	case FormatScramSHA256:
+		return hashScramSHA256(password, params)
+	}
+	return "", invalidFormatError
+}
+
+func hashBcrypt(password string, params *Params) (string, error) {
+	h, err := bcrypt.GenerateFromPassword([]byte(password), params.BcryptCost)
+	if err != nil {
+		return "", err
+	}
+	return string(h), nil
+}
+
+// hashArgon2id PHC字符串格式
+func hashArgon2id(password string, params *Params) (string, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, params.Argon2Time, params.Argon2Memory, params.Argon2Threads, 32)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, params.Argon2Memory,
+		params.Argon2Time, params.Argon2Threads, b64(salt), b64(key)), nil
+}
+
+// hashScrypt PHC字符串格式
+func hashScrypt(password string, params *Params) (string, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return "", err
+	}
+	key, err := scrypt.Key([]byte(password), salt, 1<<params.ScryptLogN, params.ScryptR, params.ScryptP, 32)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s", params.ScryptLogN, params.ScryptR, params.ScryptP,
+		b64(salt), b64(key)), nil
+}
+
+// hashPBKDF2SHA256 PHC字符串格式
+func hashPBKDF2SHA256(password string, params *Params) (string, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return "", err
+	}
+	key := pbkdf2.Key([]byte(password), salt, params.PBKDF2Iterations, 32, sha256.New)
+	return fmt.Sprintf("$pbkdf2-sha256$i=%d,l=32$%s$%s", params.PBKDF2Iterations, b64(salt), b64(key)), nil
+}
+
+// hashScramSHA256 PostgreSQL的SCRAM-SHA-256校验值
+func hashScramSHA256(password string, params *Params) (string, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return "", err
+	}
+	salted := pbkdf2.Key([]byte(password), salt, params.ScramIterations, sha256.Size, sha256.New)
+	clientKey := hmacSHA256(salted, "Client Key")
+	storedKey := sha256.Sum256(clientKey)
+	serverKey := hmacSHA256(salted, "Server Key")
+	enc := base64.StdEncoding
+	return fmt.Sprintf("SCRAM-SHA-256$%d:%s$%s:%s", params.ScramIterations, enc.EncodeToString(salt),
+		enc.EncodeToString(storedKey[:]), enc.EncodeToString(serverKey)), nil
+}
+
+func hmacSHA256(key []byte, message string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(message))
+	return mac.Sum(nil)
+}
+
+func newSalt() ([]byte, error) {
+	salt := make([]byte, saltLength)
+	if _, err := io.ReadFull(randReader, salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+func b64(b []byte) string {
+	return base64.RawStdEncoding.EncodeToString(b)
+}
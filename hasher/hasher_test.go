@@ -0,0 +1,296 @@
+package hasher
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+	"io"
+	"passwdgen/errcode"
+	"strings"
+	"testing"
+)
+
+// testParams 降低代价以加快测试
+func testParams() *Params {
+	return &Params{
+		BcryptCost:        bcrypt.MinCost,
+		Argon2Time:        1,
+		Argon2Memory:      64,
+		Argon2Threads:     1,
+		ScryptLogN:        4,
+		ScryptR:           8,
+		ScryptP:           1,
+		PBKDF2Iterations:  1000,
+		SHA512CryptRounds: 1000,
+		ScramIterations:   4096,
+	}
+}
+
+// TestSHA512CryptVectors Ulrich Drepper 的 SHA-crypt 规范中的测试向量
+func TestSHA512CryptVectors(t *testing.T) {
+	tests := []struct {
+		setting  string
+		password string
+		want     string
+	}{
+		{"$6$saltstring", "Hello world!",
+			"$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1"},
+		{"$6$rounds=10000$saltstringsaltstring", "Hello world!",
+			"$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v."},
+		{"$6$rounds=5000$toolongsaltstring", "This is just a test",
+			"$6$rounds=5000$toolongsaltstrin$lQ8jolhgVRVhY4b5pZKaysCLi0QBxGoNeKQzQ3glMhwllF7oGDZxUhx1yxdYcz/e1JSbq3y6JMxxl8audkUEm0"},
+		{"$6$rounds=1400$anotherlongsaltstring",
+			"a very much longer text to encrypt.  This one even stretches over morethan one line.",
+			"$6$rounds=1400$anotherlongsalts$POfYwTEok97VWcjxIiSOjiykti.o/pQs.wPvMxQ6Fm7I6IoYN3CmLs66x9t0oSwbtEW7o7UmJEiDwGqd8p4ur1"},
+		{"$6$rounds=77777$short", "we have a short salt string but not a short password",
+			"$6$rounds=77777$short$WuQyW2YR.hBNpjjRhpYD/ifIw05xdfeEyQoMxIXbkvr0gge1a1x3yRULJ5CCaUeOxFmtlcGZelFl5CxtgfiAc0"},
+		{"$6$rounds=123456$asaltof16chars..", "a short string",
+			"$6$rounds=123456$asaltof16chars..$BtCwjqMJGx5hrJhZywWvt0RLE8uZ4oPwcelCjmw2kSYu.Ec6ycULevoBK25fs2xXgMNrCzIMVcgEJAstJeonj1"},
+		{"$6$rounds=10$roundstoolow", "the minimum number is still observed",
+			"$6$rounds=1000$roundstoolow$kUMsbe306n21p9R.FRkW3IGn.S9NPN0x50YhH1xhLsPuWGsUSklZt58jaTfF4ZEQpyUNGc0dqbpBYYBaHHrsX."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.setting, func(t *testing.T) {
+			got, err := sha512CryptWithSetting(tt.password, tt.setting)
+			if err != nil {
+				t.Fatalf("sha512CryptWithSetting() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("sha512CryptWithSetting() = %q, want %q", got, tt.want)
+			}
+			// 用结果本身作为设置可以重新得到相同的哈希
+			if again, err := sha512CryptWithSetting(tt.password, got); err != nil || again != got {
+				t.Errorf("sha512CryptWithSetting(hash) = %q, %v, want %q", again, err, got)
+			}
+		})
+	}
+	for _, setting := range []string{"$5$saltstring", "$6$rounds=$salt", "$6$rounds=abc$salt", "$6$rounds=1000"} {
+		if _, err := sha512CryptWithSetting("password", setting); !errors.Is(err, invalidFormatError) {
+			t.Errorf("sha512CryptWithSetting(%q) error = %v, want %v", setting, err, invalidFormatError)
+		}
+	}
+}
+
+func TestSHA512CryptRoundTrip(t *testing.T) {
+	params := testParams()
+	for _, rounds := range []int{sha512CryptDefaultRounds, sha512CryptMinRounds} {
+		params.SHA512CryptRounds = rounds
+		h, err := Hash(FormatSHA512Crypt, "correct horse", params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := sha512CryptWithSetting("correct horse", h); err != nil || got != h {
+			t.Errorf("rounds %d: verify %q = %q, %v", rounds, h, got, err)
+		}
+		if hasRounds := strings.Contains(h, "$rounds="); hasRounds != (rounds != sha512CryptDefaultRounds) {
+			t.Errorf("rounds %d: hash %q", rounds, h)
+		}
+	}
+}
+
+// phcFields 解析 $id$params$salt$hash 格式,version 字段为可选
+func phcFields(t *testing.T, h, id string) (params map[string]int, salt, key []byte) {
+	t.Helper()
+	parts := strings.Split(strings.TrimPrefix(h, "$"+id+"$"), "$")
+	if len(parts) == 4 {
+		parts = parts[1:]
+	}
+	if !strings.HasPrefix(h, "$"+id+"$") || len(parts) != 3 {
+		t.Fatalf("hash %q is not a $%s$ PHC string", h, id)
+	}
+	params = make(map[string]int)
+	for _, kv := range strings.Split(parts[0], ",") {
+		var name string
+		var value int
+		if _, err := fmt.Sscanf(strings.Replace(kv, "=", " ", 1), "%s %d", &name, &value); err != nil {
+			t.Fatalf("hash %q: param %q: %v", h, kv, err)
+		}
+		params[name] = value
+	}
+	var err error
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[1]); err != nil {
+		t.Fatal(err)
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[2]); err != nil {
+		t.Fatal(err)
+	}
+	return params, salt, key
+}
+
+// verifyScram 按 RFC 5802 模拟一次认证,服务端只使用保存的 StoredKey 和 ServerKey 校验客户端证明
+func verifyScram(t *testing.T, h, password string) bool {
+	t.Helper()
+	var iterations int
+	fields := strings.FieldsFunc(strings.TrimPrefix(h, "SCRAM-SHA-256$"), func(r rune) bool {
+		return r == '$' || r == ':'
+	})
+	if !strings.HasPrefix(h, "SCRAM-SHA-256$") || len(fields) != 4 {
+		t.Fatalf("hash %q is not a SCRAM-SHA-256 verifier", h)
+	}
+	if _, err := fmt.Sscanf(fields[0], "%d", &iterations); err != nil {
+		t.Fatal(err)
+	}
+	salt64, stored64, server64 := fields[1], fields[2], fields[3]
+	enc := base64.StdEncoding
+	salt, _ := enc.DecodeString(salt64)
+	storedKey, _ := enc.DecodeString(stored64)
+	serverKey, _ := enc.DecodeString(server64)
+	authMessage := "n=user,r=nonce,r=nonce-server,s=" + salt64 + ",i=" + fmt.Sprint(iterations) + ",c=biws,r=nonce-server"
+	// 客户端
+	salted := pbkdf2.Key([]byte(password), salt, iterations, sha256.Size, sha256.New)
+	clientKey := hmacSHA256(salted, "Client Key")
+	clientSignature := hmacSHA256(sha256Sum(clientKey), authMessage)
+	proof := make([]byte, len(clientKey))
+	for i := range proof {
+		proof[i] = clientKey[i] ^ clientSignature[i]
+	}
+	// 服务端
+	signature := hmacSHA256(storedKey, authMessage)
+	recovered := make([]byte, len(proof))
+	for i := range recovered {
+		recovered[i] = proof[i] ^ signature[i]
+	}
+	if !hmac.Equal(sha256Sum(recovered), storedKey) {
+		return false
+	}
+	// 客户端校验服务端签名
+	return hmac.Equal(hmacSHA256(serverKey, authMessage), hmacSHA256(hmacSHA256(salted, "Server Key"), authMessage))
+}
+
+func sha256Sum(b []byte) []byte {
+	sum := sha256.Sum256(b)
+	return sum[:]
+}
+
+// verify 使用各个格式的参考实现校验哈希
+func verify(t *testing.T, format Format, h, password string) bool {
+	t.Helper()
+	switch format {
+	case FormatBcrypt, FormatHtpasswd:
+		return bcrypt.CompareHashAndPassword([]byte(h), []byte(password)) == nil
+	case FormatArgon2id:
+		params, salt, key := phcFields(t, h, "argon2id")
+		got := argon2.IDKey([]byte(password), salt, uint32(params["t"]), uint32(params["m"]), uint8(params["p"]),
+			uint32(len(key)))
+		return subtle.ConstantTimeCompare(got, key) == 1
+	case FormatScrypt:
+		params, salt, key := phcFields(t, h, "scrypt")
+		got, err := scrypt.Key([]byte(password), salt, 1<<params["ln"], params["r"], params["p"], len(key))
+		return err == nil && subtle.ConstantTimeCompare(got, key) == 1
+	case FormatPBKDF2SHA256:
+		params, salt, key := phcFields(t, h, "pbkdf2-sha256")
+		got := pbkdf2.Key([]byte(password), salt, params["i"], params["l"], sha256.New)
+		return subtle.ConstantTimeCompare(got, key) == 1
+	case FormatSHA512Crypt:
+		got, err := sha512CryptWithSetting(password, h)
+		return err == nil && got == h
+	case FormatScramSHA256:
+		return verifyScram(t, h, password)
+	}
+	t.Fatalf("no verifier for %s", format)
+	return false
+}
+
+func TestHashRoundTrip(t *testing.T) {
+	params := testParams()
+	for _, format := range Formats {
+		t.Run(string(format), func(t *testing.T) {
+			for _, password := range []string{"correct horse battery staple", "密码🔑", ""} {
+				h, err := Hash(format, password, params)
+				if err != nil {
+					t.Fatalf("Hash(%q) error = %v", password, err)
+				}
+				if !verify(t, format, h, password) {
+					t.Errorf("hash %q does not verify %q", h, password)
+				}
+				if verify(t, format, h, password+"x") {
+					t.Errorf("hash %q verifies a wrong password", h)
+				}
+			}
+		})
+	}
+}
+
+func TestHtpasswdPrefix(t *testing.T) {
+	h, err := Hash(FormatHtpasswd, "password", testParams())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(h, "$2y$") {
+		t.Errorf("htpasswd hash %q does not start with $2y$", h)
+	}
+}
+
+// TestScramVector RFC 7677 中 SCRAM-SHA-256 的示例
+func TestScramVector(t *testing.T) {
+	salt, _ := base64.StdEncoding.DecodeString("W22ZaJ0SNY7soEsUEjb6gQ==")
+	defer func(r io.Reader) { randReader = r }(randReader)
+	randReader = bytes.NewReader(salt)
+	params := testParams()
+	params.ScramIterations = 4096
+	h, err := Hash(FormatScramSHA256, "pencil", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "SCRAM-SHA-256$4096:W22ZaJ0SNY7soEsUEjb6gQ==$WG5d8oPm3OtcPnkdi4Uo7BkeZkBFzpcXkuLmtbsT4qY=:" +
+		"wfPLwcE6nTWhTAmQ7tl2KeoiWGPlZqQxSrmfPwDl2dU="
+	if h != want {
+		t.Fatalf("Hash() = %q, want %q", h, want)
+	}
+	// RFC 7677 的客户端证明
+	storedKey, _ := base64.StdEncoding.DecodeString("WG5d8oPm3OtcPnkdi4Uo7BkeZkBFzpcXkuLmtbsT4qY=")
+	proof, _ := base64.StdEncoding.DecodeString("dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ=")
+	authMessage := "n=user,r=rOprNGfwEbeRWgbNEkqO,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0," +
+		"s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096,c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0"
+	signature := hmacSHA256(storedKey, authMessage)
+	clientKey := make([]byte, len(proof))
+	for i := range clientKey {
+		clientKey[i] = proof[i] ^ signature[i]
+	}
+	if !hmac.Equal(sha256Sum(clientKey), storedKey) {
+		t.Error("RFC 7677 client proof does not verify")
+	}
+}
+
+func TestParamsValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(p *Params)
+		format Format
+	}{
+		{"bcrypt cost", func(p *Params) { p.BcryptCost = bcrypt.MaxCost + 1 }, FormatBcrypt},
+		{"argon2 memory", func(p *Params) { p.Argon2Memory = 8*uint32(p.Argon2Threads) - 1 }, FormatArgon2id},
+		{"argon2 threads", func(p *Params) { p.Argon2Threads = 0 }, FormatArgon2id},
+		{"scrypt ln", func(p *Params) { p.ScryptLogN = 31 }, FormatScrypt},
+		{"pbkdf2", func(p *Params) { p.PBKDF2Iterations = 0 }, FormatPBKDF2SHA256},
+		{"sha512 rounds", func(p *Params) { p.SHA512CryptRounds = sha512CryptMinRounds - 1 }, FormatSHA512Crypt},
+		{"scram", func(p *Params) { p.ScramIterations = 0 }, FormatScramSHA256},
+	}
+	if err := NewDefaultParams().Validate(); err != nil {
+		t.Fatalf("default params: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testParams()
+			tt.modify(p)
+			var codeErr *errcode.Error
+			if err := p.Validate(); !errors.As(err, &codeErr) || codeErr.Code != ErrorInvalidParams {
+				t.Errorf("Validate() = %v, want %s", err, ErrorInvalidParams)
+			}
+			if _, err := Hash(tt.format, "password", p); err != invalidParamsError {
+				t.Errorf("Hash(%s) error = %v, want %v", tt.format, err, invalidParamsError)
+			}
+		})
+	}
+	if _, err := Hash("md5", "password", testParams()); err != invalidFormatError {
+		t.Errorf("Hash(md5) error = %v, want %v", err, invalidFormatError)
+	}
+}
@@ -0,0 +1,164 @@
+package hasher
+
+import (
+	"crypto/sha512"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	sha512CryptDefaultRounds = 5000
+	sha512CryptMinRounds     = 1000
+	sha512CryptMaxRounds     = 999999999
+	sha512CryptSaltLength    = 16
+	cryptAlphabet            = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// hashSHA512Crypt /etc/shadow 使用的 $6$ 格式
+func hashSHA512Crypt(password string, params *Params) (string, error) {
+	rounds := params.SHA512CryptRounds
+	salt := make([]byte, sha512CryptSaltLength)
+	if _, err := io.ReadFull(randReader, salt); err != nil {
+		return "", err
+	}
+	for i := range salt {
+		salt[i] = cryptAlphabet[int(salt[i])%len(cryptAlphabet)]
+	}
+	return sha512Crypt([]byte(password), salt, rounds, rounds != sha512CryptDefaultRounds), nil
+}
+
+// sha512CryptWithSetting 按 $6$[rounds=N$]salt 格式的设置计算哈希,与 crypt(3) 一致:
+// 盐超过16个字符时截断,轮次超出范围时取最近的边界,显式指定的轮次总是写入结果
+func sha512CryptWithSetting(password, setting string) (string, error) {
+	rest := strings.TrimPrefix(setting, "$6$")
+	if rest == setting {
+		return "", invalidFormatError
+	}
+	rounds, custom := sha512CryptDefaultRounds, false
+	if strings.HasPrefix(rest, "rounds=") {
+		value, after, ok := strings.Cut(strings.TrimPrefix(rest, "rounds="), "$")
+		n, err := strconv.ParseUint(value, 10, 64)
+		if !ok || err != nil {
+			return "", invalidFormatError
+		}
+		switch {
+		case n < sha512CryptMinRounds:
+			rounds = sha512CryptMinRounds
+		case n > sha512CryptMaxRounds:
+			rounds = sha512CryptMaxRounds
+		default:
+			rounds = int(n)
+		}
+		rest, custom = after, true
+	}
+	salt, _, _ := strings.Cut(rest, "$")
+	if len(salt) > sha512CryptSaltLength {
+		salt = salt[:sha512CryptSaltLength]
+	}
+	return sha512Crypt([]byte(password), []byte(salt), rounds, custom), nil
+}
+
+// sha512Crypt 按照 Ulrich Drepper 的 SHA-crypt 规范实现,customRounds 为true时结果中写入轮次
+func sha512Crypt(password, salt []byte, rounds int, customRounds bool) string {
+	// 摘要B
+	b := sha512.New()
+	b.Write(password)
+	b.Write(salt)
+	b.Write(password)
+	digestB := b.Sum(nil)
+	// 摘要A
+	a := sha512.New()
+	a.Write(password)
+	a.Write(salt)
+	for i := len(password); i > 0; i -= 64 {
+		if i > 64 {
+			a.Write(digestB)
+		} else {
+			a.Write(digestB[:i])
+		}
+	}
+	for i := len(password); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			a.Write(digestB)
+		} else {
+			a.Write(password)
+		}
+	}
+	digestA := a.Sum(nil)
+	// 序列P
+	dp := sha512.New()
+	for i := 0; i < len(password); i++ {
+		dp.Write(password)
+	}
+	digestDP := dp.Sum(nil)
+	p := make([]byte, 0, len(password))
+	for i := len(password); i > 0; i -= 64 {
+		if i > 64 {
+			p = append(p, digestDP...)
+		} else {
+			p = append(p, digestDP[:i]...)
+		}
+	}
+	// 序列S
+	ds := sha512.New()
+	for i := 0; i < 16+int(digestA[0]); i++ {
+		ds.Write(salt)
+	}
+	digestDS := ds.Sum(nil)
+	s := digestDS[:len(salt)]
+	// 轮次
+	c := digestA
+	for i := 0; i < rounds; i++ {
+		h := sha512.New()
+		if i&1 != 0 {
+			h.Write(p)
+		} else {
+			h.Write(c)
+		}
+		if i%3 != 0 {
+			h.Write(s)
+		}
+		if i%7 != 0 {
+			h.Write(p)
+		}
+		if i&1 != 0 {
+			h.Write(c)
+		} else {
+			h.Write(p)
+		}
+		c = h.Sum(nil)
+	}
+	out := []byte("$6$")
+	if customRounds {
+		out = append(out, "rounds="...)
+		out = strconv.AppendInt(out, int64(rounds), 10)
+		out = append(out, '$')
+	}
+	out = append(out, salt...)
+	out = append(out, '$')
+	return string(append(out, cryptBase64(c)...))
+}
+
+// sha512CryptPermutation 输出编码时的字节顺序
+var sha512CryptPermutation = [][3]int{
+	{0, 21, 42}, {22, 43, 1}, {44, 2, 23}, {3, 24, 45}, {25, 46, 4}, {47, 5, 26}, {6, 27, 48},
+	{28, 49, 7}, {50, 8, 29}, {9, 30, 51}, {31, 52, 10}, {53, 11, 32}, {12, 33, 54}, {34, 55, 13},
+	{56, 14, 35}, {15, 36, 57}, {37, 58, 16}, {59, 17, 38}, {18, 39, 60}, {40, 61, 19}, {62, 20, 41},
+}
+
+func cryptBase64(c []byte) []byte {
+	out := make([]byte, 0, 86)
+	encode := func(b2, b1, b0 byte, n int) {
+		w := uint(b2)<<16 | uint(b1)<<8 | uint(b0)
+		for ; n > 0; n-- {
+			out = append(out, cryptAlphabet[w&0x3f])
+			w >>= 6
+		}
+	}
+	for _, idx := range sha512CryptPermutation {
+		encode(c[idx[0]], c[idx[1]], c[idx[2]], 4)
+	}
+	encode(0, 0, c[63], 2)
+	return out
+}
@@ -0,0 +1,122 @@
+package history
+
+import (
+	"bytes"
+	"github.com/BurntSushi/toml"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// MaxEntries 保存的历史记录上限,超出时丢弃最早的记录
+const MaxEntries = 1000
+
+// Entry 一条生成记录
+type Entry struct {
+	Password string    `toml:"password"`
+	Created  time.Time `toml:"created"`
+}
+
+type historyFile struct {
+	Entries []*Entry `toml:"entry"`
+}
+
+// Store 保存在文件中的历史记录。文件包含明文密码,只有当前用户可读写
+type Store struct {
+	path    string
+	mu      sync.Mutex
+	entries []*Entry
+}
+
+// Open 打开指定路径的历史记录,文件不存在时返回空记录
+func Open(path string) (*Store, error) {
+	store := &Store{path: path}
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, err
+	}
+	var hf historyFile
+	if err := toml.Unmarshal(buf, &hf); err != nil {
+		return nil, err
+	}
+	store.entries = hf.Entries
+	return store, nil
+}
+
+// Path 返回历史记录文件的路径
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Entries 按生成时间从早到晚返回所有记录
+func (s *Store) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := make([]Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, *e)
+	}
+	return entries
+}
+
+// Add 追加一条记录并写回文件
+func (s *Store) Add(entry Entry) error {
+	return s.update(func() {
+		s.entries = append(s.entries, &entry)
+		if len(s.entries) > MaxEntries {
+			s.entries = s.entries[len(s.entries)-MaxEntries:]
+		}
+	})
+}
+
+// Remove 删除第i条记录并写回文件,i超出范围时不做修改
+func (s *Store) Remove(i int) error {
+	return s.update(func() {
+		if i >= 0 && i < len(s.entries) {
+			s.entries = append(s.entries[:i:i], s.entries[i+1:]...)
+		}
+	})
+}
+
+// Clear 删除所有记录和历史记录文件
+func (s *Store) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	s.entries = nil
+	return nil
+}
+
+// update 在锁内修改记录并写回文件,失败时回滚
+func (s *Store) update(m func()) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	backup := s.entries
+	m()
+	if err := s.flush(); err != nil {
+		s.entries = backup
+		return err
+	}
+	return nil
+}
+
+func (s *Store) flush() error {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(&historyFile{Entries: s.entries}); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
@@ -0,0 +1,110 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestStorePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "passwdgen", "history.toml")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Entries()) != 0 {
+		t.Fatalf("new store has %d entries", len(s.Entries()))
+	}
+	created := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	for _, password := range []string{"first", "second", "third"} {
+		if err := s.Add(Entry{Password: password, Created: created}); err != nil {
+			t.Fatalf("Add(%q) error = %v", password, err)
+		}
+	}
+	if err := s.Remove(1); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := reopened.Entries()
+	if len(entries) != 2 || entries[0].Password != "first" || entries[1].Password != "third" {
+		t.Fatalf("Entries() = %+v", entries)
+	}
+	if !entries[0].Created.Equal(created) {
+		t.Errorf("Created = %v, want %v", entries[0].Created, created)
+	}
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := fi.Mode().Perm(); perm != 0o600 {
+			t.Errorf("history file mode = %o, want 600", perm)
+		}
+	}
+	if err := reopened.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("history file still exists after Clear: %v", err)
+	}
+	if len(reopened.Entries()) != 0 {
+		t.Errorf("Entries() after Clear = %+v", reopened.Entries())
+	}
+}
+
+func TestStoreLimit(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "history.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.entries = make([]*Entry, MaxEntries)
+	for i := range s.entries {
+		s.entries[i] = &Entry{Password: "old"}
+	}
+	if err := s.Add(Entry{Password: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	entries := s.Entries()
+	if len(entries) != MaxEntries || entries[len(entries)-1].Password != "new" {
+		t.Errorf("got %d entries, last %q", len(entries), entries[len(entries)-1].Password)
+	}
+}
+
+func TestStoreRollback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.toml")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(Entry{Password: "kept"}); err != nil {
+		t.Fatal(err)
+	}
+	// 临时文件的位置被目录占用,写回失败
+	if err := os.Mkdir(path+".tmp", 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(Entry{Password: "lost"}); err == nil {
+		t.Fatal("Add() succeeded although the file could not be written")
+	}
+	if err := s.Remove(0); err == nil {
+		t.Fatal("Remove() succeeded although the file could not be written")
+	}
+	if entries := s.Entries(); len(entries) != 1 || entries[0].Password != "kept" {
+		t.Errorf("Entries() after failed writes = %+v", entries)
+	}
+}
+
+func TestOpenInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.toml")
+	if err := os.WriteFile(path, []byte("[[entry]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path); err == nil {
+		t.Error("Open() accepted a malformed file")
+	}
+}
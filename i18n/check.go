@@ -0,0 +1,111 @@
+package i18n
+
+import (
+	"fmt"
+	"github.com/BurntSushi/toml"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"golang.org/x/text/language"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// EmbeddedPrefix 内置语言包在检查结果中的路径前缀
+const EmbeddedPrefix = "embedded:"
+
+var unmarshalFuncs = map[string]goi18n.UnmarshalFunc{"toml": toml.Unmarshal}
+
+// BundleReport 语言包的检查结果
+type BundleReport struct {
+	// 语言包路径,内置语言包以 EmbeddedPrefix 开头
+	Path string
+	Tag  language.Tag
+	// 语言包中的消息数量
+	Messages int
+	// MessageIds 中定义或错误码登记但语言包缺失或为空的消息
+	Missing []MessageId
+	// 语言包中有但 MessageIds 未定义的消息
+	Unused []string
+}
+
+// CheckBundles 检查内置语言包和用户目录中的语言包,无法解析的语言包合并为 *LoadError 返回
+func CheckBundles() ([]*BundleReport, error) {
+	paths, err := embeddedPaths()
+	if err != nil {
+		return nil, err
+	}
+	reports, errs := checkFiles(paths, EmbeddedPrefix, LocaleFS.ReadFile)
+	if dir, err := DefaultDir(); err == nil {
+		paths, err := filepath.Glob(filepath.Join(dir, LocalePattern))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(paths)
+		userReports, userErrs := checkFiles(paths, "", os.ReadFile)
+		reports = append(reports, userReports...)
+		errs = append(errs, userErrs...)
+	}
+	if len(errs) > 0 {
+		return reports, &LoadError{Errors: errs}
+	}
+	return reports, nil
+}
+
+// CheckFiles 检查语言包文件,无法读取或解析的文件合并为 *LoadError 返回
+func CheckFiles(paths []string) ([]*BundleReport, error) {
+	reports, errs := checkFiles(paths, "", os.ReadFile)
+	if len(errs) > 0 {
+		return reports, &LoadError{Errors: errs}
+	}
+	return reports, nil
+}
+
+func checkFiles(paths []string, prefix string, readFile func(path string) ([]byte, error)) ([]*BundleReport, []error) {
+	var reports []*BundleReport
+	var errs []error
+	for _, path := range paths {
+		buf, err := readFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		report, err := checkMessageFile(prefix+path, buf, ErrorMessageIds())
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		reports = append(reports, report)
+	}
+	return reports, errs
+}
+
+// checkMessageFile 检查语言包是否包含 MessageIds 和 errorMessageIds 中的消息,
+// errorMessageIds 是各个包登记的错误码消息ID
+func checkMessageFile(path string, buf []byte, errorMessageIds []MessageId) (*BundleReport, error) {
+	mf, err := goi18n.ParseMessageFileBytes(buf, path, unmarshalFuncs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	report := &BundleReport{Path: path, Tag: mf.Tag, Messages: len(mf.Messages)}
+	messages := make(map[string]*goi18n.Message, len(mf.Messages))
+	for _, message := range mf.Messages {
+		messages[message.ID] = message
+	}
+	known := make(map[string]bool, len(MessageIds))
+	for _, messageId := range append(append([]MessageId{}, MessageIds...), errorMessageIds...) {
+		if known[string(messageId)] {
+			continue
+		}
+		known[string(messageId)] = true
+		if message, ok := messages[string(messageId)]; !ok || message.Other == "" {
+			report.Missing = append(report.Missing, messageId)
+		}
+	}
+	for _, message := range mf.Messages {
+		if !known[message.ID] {
+			report.Unused = append(report.Unused, message.ID)
+		}
+	}
+	sort.Strings(report.Unused)
+	return report, nil
+}
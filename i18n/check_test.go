@@ -0,0 +1,51 @@
+package i18n
+
+import (
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"testing"
+)
+
+// TestMessageIdsGenerated 检查 keys.go 与英文语言包一致,不一致时需要运行 go generate ./i18n
+func TestMessageIdsGenerated(t *testing.T) {
+	buf, err := LocaleFS.ReadFile(fmt.Sprintf(LocaleTemplate, "en"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var messages map[string]interface{}
+	md, err := toml.Decode(string(buf), &messages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []MessageId
+	for _, key := range md.Keys() {
+		if len(key) == 1 {
+			ids = append(ids, MessageId(key[0]))
+		}
+	}
+	if len(ids) != len(MessageIds) {
+		t.Fatalf("locale.en.toml has %d messages, MessageIds has %d", len(ids), len(MessageIds))
+	}
+	for i, id := range ids {
+		if MessageIds[i] != id {
+			t.Fatalf("MessageIds[%d] = %q, locale.en.toml has %q", i, MessageIds[i], id)
+		}
+	}
+}
+
+func TestCheckMessageFileRegisteredErrors(t *testing.T) {
+	buf, err := LocaleFS.ReadFile(fmt.Sprintf(LocaleTemplate, "en"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := checkMessageFile("locale.en.toml", buf, []MessageId{ErrorProfileNotFoundMessageKey, "TestOnlyErrorMessage"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Missing) != 1 || report.Missing[0] != "TestOnlyErrorMessage" {
+		t.Errorf("Missing = %v, want [TestOnlyErrorMessage]", report.Missing)
+	}
+	if len(report.Unused) != 0 {
+		t.Errorf("Unused = %v, want none", report.Unused)
+	}
+}
@@ -0,0 +1,50 @@
+package i18n_test
+
+import (
+	"fmt"
+	"passwdgen/config"
+	"passwdgen/errcode"
+	"passwdgen/gen"
+	_ "passwdgen/hasher"
+	"passwdgen/i18n"
+	_ "passwdgen/profile"
+	_ "passwdgen/qr"
+	_ "passwdgen/server"
+	_ "passwdgen/shamir"
+	_ "passwdgen/sheet"
+	_ "passwdgen/theme"
+	_ "passwdgen/wordlist"
+	"testing"
+)
+
+// TestRegisteredErrorMessages 各个包登记的错误码都要有对应的消息
+func TestRegisteredErrorMessages(t *testing.T) {
+	known := make(map[i18n.MessageId]bool, len(i18n.MessageIds))
+	for _, messageId := range i18n.MessageIds {
+		known[messageId] = true
+	}
+	codes := errcode.Codes()
+	if len(codes) == 0 {
+		t.Fatal("no error codes registered")
+	}
+	for _, code := range codes {
+		messageId, _ := errcode.MessageId(code)
+		if !known[i18n.MessageId(messageId)] {
+			t.Errorf("error code %q: message %q not in MessageIds", code, messageId)
+		}
+	}
+}
+
+func TestLocalizeValidationError(t *testing.T) {
+	err := fmt.Errorf("load: %w", &config.ValidationError{Problems: []config.Problem{
+		{Reason: "generation settings cannot produce a password", Err: errcode.New(gen.ErrorInvalidLength)},
+		{Key: "generation.length", Origin: config.Origin{Source: config.SourceEnv, Location: "PASSWDGEN_LENGTH"},
+			Err: fmt.Errorf("length: %w", errcode.New(gen.ErrorInvalidLength))},
+	}})
+	want := "load: invalid configuration (2 problem(s)):\n" +
+		"  generation settings cannot produce a password: 密码长度异常\n" +
+		"  generation.length (env PASSWDGEN_LENGTH): length: 密码长度异常"
+	if got := i18n.LocalizeError("zh", err); got != want {
+		t.Errorf("LocalizeError() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,128 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"passwdgen/errcode"
+	"strings"
+)
+
+// strengthNames 强度等级的名称,从弱到强排列,与 gen.Strength 的 String 一致
+var strengthNames = []string{"unknown", "very_weak", "weak", "normal", "strong", "very_strong"}
+
+// strengthMessageIds 强度等级对应的显示文本
+var strengthMessageIds = map[string]MessageId{
+	"unknown":     StrengthUnknownInfoKey,
+	"very_weak":   StrengthVeryWeakInfoKey,
+	"weak":        StrengthWeakInfoKey,
+	"normal":      StrengthNormalInfoKey,
+	"strong":      StrengthStrongInfoKey,
+	"very_strong": StrengthVeryStrongInfoKey,
+}
+
+// strengthCostMessageIds 强度等级对应的破解时间,未知等级没有破解时间
+var strengthCostMessageIds = map[string]MessageId{
+	"very_weak":   StrengthVeryWeakCostKey,
+	"weak":        StrengthWeakCostKey,
+	"normal":      StrengthNormalCostKey,
+	"strong":      StrengthStrongCostKey,
+	"very_strong": StrengthVeryStrongCostKey,
+}
+
+// themeMessageIds 内置主题的显示名称,键与 theme 包中内置主题的名称一致
+var themeMessageIds = map[string]MessageId{
+	"Dark":   ThemeDarkNameKey,
+	"Light":  ThemeLightNameKey,
+	"System": ThemeSystemNameKey,
+}
+
+// StrengthMessageId 返回强度等级的消息ID,strength 通常是 gen.Strength
+func StrengthMessageId(strength fmt.Stringer) MessageId {
+	if messageId, ok := strengthMessageIds[strength.String()]; ok {
+		return messageId
+	}
+	return StrengthUnknownInfoKey
+}
+
+// StrengthCostMessageId 返回强度等级对应破解时间的消息ID,未知等级返回false
+func StrengthCostMessageId(strength fmt.Stringer) (MessageId, bool) {
+	messageId, ok := strengthCostMessageIds[strength.String()]
+	return messageId, ok
+}
+
+// StrengthMessageIds 返回所有强度等级和破解时间的消息ID,用于注册刷新器
+func StrengthMessageIds() []MessageId {
+	messageIds := make([]MessageId, 0, len(strengthMessageIds)+len(strengthCostMessageIds))
+	for _, name := range strengthNames {
+		messageIds = append(messageIds, strengthMessageIds[name])
+		if messageId, ok := strengthCostMessageIds[name]; ok {
+			messageIds = append(messageIds, messageId)
+		}
+	}
+	return messageIds
+}
+
+// ThemeNameMessageId 返回内置主题显示名称的消息ID,其他主题没有翻译,返回false
+func ThemeNameMessageId(name string) (MessageId, bool) {
+	messageId, ok := themeMessageIds[name]
+	return messageId, ok
+}
+
+// ErrorMessageId 返回错误对应的消息ID,err 不是带错误码的 *errcode.Error 或错误码未登记时返回false
+func ErrorMessageId(err error) (MessageId, bool) {
+	var codeErr *errcode.Error
+	if !errors.As(err, &codeErr) {
+		return "", false
+	}
+	messageId, ok := errcode.MessageId(codeErr.Code)
+	return MessageId(messageId), ok
+}
+
+// ErrorMessageIds 返回已登记错误码的消息ID,按错误码排序
+func ErrorMessageIds() []MessageId {
+	codes := errcode.Codes()
+	messageIds := make([]MessageId, 0, len(codes))
+	for _, code := range codes {
+		messageId, _ := errcode.MessageId(code)
+		messageIds = append(messageIds, MessageId(messageId))
+	}
+	return messageIds
+}
+
+// LocalizeError 按语言翻译带错误码的错误,保留外层包装的上下文,其余错误原样返回文本
+func LocalizeError(lang string, err error) string {
+	return LocalizeErrorWith(err, func(messageId MessageId) (string, bool) {
+		value, lerr := Localize(lang, messageId)
+		return value, lerr == nil
+	})
+}
+
+// MultiError 包含多个错误的错误,例如 *config.ValidationError
+type MultiError interface {
+	error
+	// ErrorWith 使用 describe 显示其中的每个错误后返回文本
+	ErrorWith(describe func(err error) string) string
+}
+
+// LocalizeErrorWith 使用 localize 把错误文本中 *errcode.Error 的部分替换为翻译后的文本,
+// MultiError 中的每个错误分别翻译
+func LocalizeErrorWith(err error, localize func(messageId MessageId) (string, bool)) string {
+	text := err.Error()
+	var multi MultiError
+	if errors.As(err, &multi) {
+		return strings.Replace(text, multi.Error(), multi.ErrorWith(func(err error) string {
+			return LocalizeErrorWith(err, localize)
+		}), 1)
+	}
+	messageId, ok := ErrorMessageId(err)
+	if !ok {
+		return text
+	}
+	value, ok := localize(messageId)
+	if !ok {
+		return text
+	}
+	var codeErr *errcode.Error
+	errors.As(err, &codeErr)
+	return strings.Replace(text, codeErr.Error(), value, 1)
+}
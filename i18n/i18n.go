@@ -3,93 +3,252 @@ package i18n
 import (
 	"embed"
 	"fmt"
-	"fyne.io/fyne/v2"
 	"github.com/BurntSushi/toml"
 	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
 	"golang.org/x/text/language"
-	"log"
+	"golang.org/x/text/language/display"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 )
 
+//go:generate go run ./internal/genkeys -in bundles/locale.en.toml -out keys.go
+
 //go:embed bundles/locale.*.toml
 var LocaleFS embed.FS
 
-const LocaleTemplate = "bundles/locale.%s.toml"
+const (
+	LocaleTemplate = "bundles/locale.%s.toml"
+	// LocalePattern 语言包文件名,例如 locale.ja.toml
+	LocalePattern = "locale.*.toml"
+
+	AppDirName = "passwdgen"
+	// DirName 用户语言包目录,位于配置目录下,启动时加载其中的 locale.<语言>.toml
+	DirName = "locales"
+)
 
-var refresherMap = make(map[MessageId][]refresher)
+// DefaultLanguage 缺失的消息回退到英文
+var DefaultLanguage = language.English
 
-var loadedBundleMap = make(map[string]*goi18n.Bundle)
+var (
+	bundleOnce sync.Once
+	bundle     *goi18n.Bundle
+	// 内置语言包的加载错误
+	bundleErr error
+	// 用户语言包的加载错误
+	userBundleErr error
+)
 
-type CanvasLocalizer struct {
-	Lang string
-	mu   sync.Mutex
+// maxListedMessages 错误信息中最多列出的消息ID数量
+const maxListedMessages = 5
+
+// LoadError 用户语言包的加载错误
+type LoadError struct {
+	Errors []error
 }
 
-func (cl *CanvasLocalizer) SwitchLang(canvasObjectsToRefresh []fyne.CanvasObject) {
-	cl.mu.Lock()
-	defer cl.mu.Unlock()
-	lang, _, ok := strings.Cut(cl.Lang, "_")
-	if !ok {
-		log.Fatal("parse lang string failed")
+func (le *LoadError) Error() string {
+	lines := make([]string, 0, len(le.Errors)+1)
+	lines = append(lines, fmt.Sprintf("invalid locale bundles (%d problem(s)):", len(le.Errors)))
+	for _, err := range le.Errors {
+		lines = append(lines, "  "+err.Error())
 	}
-	langTag, le := language.Parse(lang)
-	if le != nil {
-		log.Fatal("parse language failed")
+	return strings.Join(lines, "\n")
+}
+
+// MissingMessagesError 语言包缺失消息,缺失的消息已使用英文显示
+type MissingMessagesError struct {
+	Lang       string
+	MessageIds []MessageId
+}
+
+func (e *MissingMessagesError) Error() string {
+	ids := make([]string, 0, maxListedMessages)
+	for i, messageId := range e.MessageIds {
+		if i == maxListedMessages {
+			ids = append(ids, fmt.Sprintf("... (%d more)", len(e.MessageIds)-maxListedMessages))
+			break
+		}
+		ids = append(ids, string(messageId))
 	}
-	bundle, ok := loadedBundleMap[lang]
-	if !ok {
-		bundle = goi18n.NewBundle(langTag)
+	return fmt.Sprintf("language %q is missing %d message(s), using %s instead: %s",
+		e.Lang, len(e.MessageIds), DefaultLanguage, strings.Join(ids, ", "))
+}
+
+// Language 可选的界面语言
+type Language struct {
+	Tag language.Tag
+	// 语言的本地名称,例如 中文、English
+	Name string
+}
+
+// Option 返回界面显示的选项,例如 zh(中文)
+func (l Language) Option() string {
+	return fmt.Sprintf("%s(%s)", l.Tag, l.Name)
+}
+
+// defaultBundle 加载内置语言包和用户目录中的语言包,只加载一次。
+// 内置语言包加载失败时返回错误,已加载的语言包仍可使用
+func defaultBundle() (*goi18n.Bundle, error) {
+	bundleOnce.Do(func() {
+		bundle = goi18n.NewBundle(DefaultLanguage)
 		bundle.RegisterUnmarshalFunc("toml", toml.Unmarshal)
-		_, err := bundle.LoadMessageFileFS(LocaleFS, fmt.Sprintf(LocaleTemplate, lang))
-		if err != nil {
-			log.Fatal("load message file failed")
+		bundleErr = loadEmbedded(bundle)
+		if dir, err := DefaultDir(); err == nil {
+			userBundleErr = loadDir(bundle, dir)
 		}
-		loadedBundleMap[lang] = bundle
-	}
-	localizer := goi18n.NewLocalizer(bundle, lang)
-	if len(refresherMap) > 0 {
-		refreshAll(localizer)
-		if len(canvasObjectsToRefresh) > 0 {
-			for _, co := range canvasObjectsToRefresh {
-				co.Refresh()
-			}
+	})
+	return bundle, bundleErr
+}
+
+func loadEmbedded(bundle *goi18n.Bundle) error {
+	paths, err := embeddedPaths()
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if _, err := bundle.LoadMessageFileFS(LocaleFS, path); err != nil {
+			return fmt.Errorf("load message file %s failed: %w", path, err)
 		}
 	}
+	return nil
 }
 
-func RegisterRefresher(messageId MessageId, m func(value string)) {
-	refresherCache, ok := refresherMap[messageId]
-	if !ok {
-		refresherCache = make([]refresher, 0)
+// embeddedPaths 返回内置语言包的路径
+func embeddedPaths() ([]string, error) {
+	return fs.Glob(LocaleFS, fmt.Sprintf(LocaleTemplate, "*"))
+}
+
+// DefaultDir 返回用户语言包目录
+func DefaultDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
 	}
-	refresherCache = append(refresherCache, &defaultRefresher{
-		messageId: messageId,
-		m:         m,
-	})
-	refresherMap[messageId] = refresherCache
+	return filepath.Join(dir, AppDirName, DirName), nil
+}
+
+// loadDir 加载目录中的语言包,目录不存在时忽略。
+// 与内置语言相同的语言包会覆盖同名消息,缺失的消息仍使用内置文本
+func loadDir(bundle *goi18n.Bundle, dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, LocalePattern))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+	var errs []error
+	for _, path := range paths {
+		if _, err := bundle.LoadMessageFile(path); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+		}
+	}
+	if len(errs) > 0 {
+		return &LoadError{Errors: errs}
+	}
+	return nil
+}
+
+// UserBundleError 返回用户语言包的加载错误
+func UserBundleError() error {
+	_, _ = defaultBundle()
+	return userBundleErr
 }
 
-func refreshAll(localizer *goi18n.Localizer) {
-	for _, refreshers := range refresherMap {
-		for _, refresher := range refreshers {
-			refresher.refresh(localizer)
+// Languages 返回所有可用的界面语言,按语言标签排序
+func Languages() []Language {
+	bundle, _ := defaultBundle()
+	tags := bundle.LanguageTags()
+	languages := make([]Language, 0, len(tags))
+	for _, tag := range tags {
+		name := display.Self.Name(tag)
+		if name == "" {
+			name = tag.String()
 		}
+		languages = append(languages, Language{Tag: tag, Name: name})
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		return languages[i].Tag.String() < languages[j].Tag.String()
+	})
+	return languages
+}
+
+// localeEnvs 按优先级排列的语言环境变量
+var localeEnvs = []string{"LC_ALL", "LC_MESSAGES", "LANG"}
+
+// DetectLanguage 从环境变量检测首选语言,并与可用的语言包匹配,没有匹配的语言包时返回false
+func DetectLanguage() (Language, bool) {
+	return detectLanguage(os.Getenv, Languages())
+}
+
+func detectLanguage(getenv func(string) string, languages []Language) (Language, bool) {
+	if len(languages) == 0 {
+		return Language{}, false
 	}
+	tag, ok := envLanguage(getenv)
+	if !ok {
+		return Language{}, false
+	}
+	tags := make([]language.Tag, 0, len(languages))
+	for _, lang := range languages {
+		tags = append(tags, lang.Tag)
+	}
+	_, index, confidence := language.NewMatcher(tags).Match(tag)
+	if confidence == language.No {
+		return Language{}, false
+	}
+	return languages[index], true
 }
 
-type refresher interface {
-	refresh(localizer *goi18n.Localizer)
+// envLanguage 读取第一个设置了的语言环境变量,例如 de_DE.UTF-8@euro。
+// 与 POSIX 一致,前面的变量有值时忽略后面的变量,C 和 POSIX 表示未指定语言
+func envLanguage(getenv func(string) string) (language.Tag, bool) {
+	for _, env := range localeEnvs {
+		value := getenv(env)
+		if value == "" {
+			continue
+		}
+		if i := strings.IndexAny(value, ".@"); i >= 0 {
+			value = value[:i]
+		}
+		if value == "C" || value == "POSIX" {
+			return language.Tag{}, false
+		}
+		tag, err := parseLang(value)
+		if err != nil {
+			return language.Tag{}, false
+		}
+		return tag, true
+	}
+	return language.Tag{}, false
 }
 
-type defaultRefresher struct {
-	messageId MessageId
-	m         func(value string)
+// parseLang 解析语言,支持 zh_CN、en-US 以及界面选项 zh(中文) 等写法
+func parseLang(lang string) (language.Tag, error) {
+	if i := strings.Index(lang, "("); i >= 0 {
+		lang = lang[:i]
+	}
+	return language.Parse(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
 }
 
-func (dr *defaultRefresher) refresh(localizer *goi18n.Localizer) {
-	value := localizer.MustLocalize(&goi18n.LocalizeConfig{
-		MessageID: string(dr.messageId),
+// Localize 按语言(例如 zh_CN 或 en)翻译消息,供命令行等没有界面的场景使用。
+// 语言缺失的消息回退到英文
+func Localize(lang string, messageId MessageId) (string, error) {
+	langTag, err := parseLang(lang)
+	if err != nil {
+		return "", err
+	}
+	bundle, err := defaultBundle()
+	if err != nil {
+		return "", err
+	}
+	value, err := goi18n.NewLocalizer(bundle, langTag.String()).Localize(&goi18n.LocalizeConfig{
+		MessageID: string(messageId),
 	})
-	dr.m(value)
+	if value != "" {
+		return value, nil
+	}
+	return value, err
 }
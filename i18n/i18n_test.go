@@ -0,0 +1,55 @@
+package i18n
+
+import (
+	"golang.org/x/text/language"
+	"testing"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	languages := []Language{
+		{Tag: language.English, Name: "English"},
+		{Tag: language.Chinese, Name: "中文"},
+		{Tag: language.Japanese, Name: "日本語"},
+		{Tag: language.German, Name: "Deutsch"},
+		{Tag: language.French, Name: "Français"},
+		{Tag: language.Spanish, Name: "Español"},
+	}
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+		ok   bool
+	}{
+		{name: "unset", env: nil},
+		{name: "empty", env: map[string]string{"LC_ALL": "", "LC_MESSAGES": "", "LANG": ""}},
+		{name: "lang", env: map[string]string{"LANG": "de_DE"}, want: "de", ok: true},
+		{name: "codeset", env: map[string]string{"LANG": "zh_CN.UTF-8"}, want: "zh", ok: true},
+		{name: "modifier", env: map[string]string{"LANG": "en_US@euro"}, want: "en", ok: true},
+		{name: "codeset and modifier", env: map[string]string{"LANG": "fr_FR.ISO-8859-15@euro"}, want: "fr", ok: true},
+		{name: "lc_messages over lang", env: map[string]string{"LC_MESSAGES": "ja_JP.UTF-8", "LANG": "de_DE.UTF-8"},
+			want: "ja", ok: true},
+		{name: "lc_all over lc_messages", env: map[string]string{"LC_ALL": "es_ES.UTF-8", "LC_MESSAGES": "ja_JP.UTF-8",
+			"LANG": "de_DE.UTF-8"}, want: "es", ok: true},
+		{name: "empty lc_all ignored", env: map[string]string{"LC_ALL": "", "LANG": "ja_JP"}, want: "ja", ok: true},
+		{name: "c", env: map[string]string{"LANG": "C"}},
+		{name: "c codeset", env: map[string]string{"LANG": "C.UTF-8"}},
+		{name: "posix", env: map[string]string{"LANG": "POSIX"}},
+		{name: "c in lc_all hides lang", env: map[string]string{"LC_ALL": "C", "LANG": "de_DE.UTF-8"}},
+		{name: "unsupported", env: map[string]string{"LANG": "ko_KR.UTF-8"}},
+		{name: "invalid", env: map[string]string{"LANG": "not a locale"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string {
+				return tt.env[key]
+			}
+			got, ok := detectLanguage(getenv, languages)
+			if ok != tt.ok {
+				t.Fatalf("detectLanguage ok = %v, want %v (language %v)", ok, tt.ok, got.Tag)
+			}
+			if ok && got.Tag.String() != tt.want {
+				t.Errorf("detectLanguage = %v, want %v", got.Tag, tt.want)
+			}
+		})
+	}
+}
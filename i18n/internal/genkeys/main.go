@@ -0,0 +1,64 @@
+// genkeys 按英文语言包生成 i18n/keys.go 中的消息ID常量和 MessageIds 列表,
+// 顺序与语言包中消息的顺序一致。新增消息时先加入 locale.en.toml,再运行 go generate ./i18n
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"go/format"
+	"log"
+	"os"
+)
+
+func main() {
+	in := flag.String("in", "bundles/locale.en.toml", "英文语言包")
+	out := flag.String("out", "keys.go", "生成的文件")
+	flag.Parse()
+	ids, err := messageIds(*in)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(ids)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// messageIds 按出现顺序返回语言包中的消息ID
+func messageIds(path string) ([]string, error) {
+	var messages map[string]interface{}
+	md, err := toml.DecodeFile(path, &messages)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, key := range md.Keys() {
+		if len(key) == 1 {
+			ids = append(ids, key[0])
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%s: no messages", path)
+	}
+	return ids, nil
+}
+
+func generate(ids []string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by genkeys from bundles/locale.en.toml. DO NOT EDIT.\n\n")
+	buf.WriteString("package i18n\n\ntype MessageId string\n\nconst (\n")
+	for _, id := range ids {
+		fmt.Fprintf(&buf, "\t%sKey MessageId = %q\n", id, id)
+	}
+	buf.WriteString(")\n\n// MessageIds 所有消息ID,i18n check 按此检查语言包\nvar MessageIds = []MessageId{\n")
+	for _, id := range ids {
+		fmt.Fprintf(&buf, "\t%sKey,\n", id)
+	}
+	buf.WriteString("}\n")
+	return format.Source(buf.Bytes())
+}
@@ -1,27 +1,414 @@
+// Code generated by genkeys from bundles/locale.en.toml. DO NOT EDIT.
+
 package i18n
 
 type MessageId string
 
 const (
-	MainWindowTitleKey                MessageId = "MainWindowTitle"
-	PassWdTabTitleKey                 MessageId = "PassWdTabTitle"
-	SettingTabTitleKey                MessageId = "SettingTabTitle"
-	SettingAppearanceCardTitleKey     MessageId = "SettingAppearanceCardTitle"
-	SettingThemeFormTitleKey          MessageId = "SettingThemeFormTitle"
-	SettingLangFormTitleKey           MessageId = "SettingLangFormTitle"
-	PasswdStrengthLabelKey            MessageId = "PasswdStrengthLabel"
-	PasswdLengthLabelKey              MessageId = "PasswdLengthLabel"
-	PasswdLengthSlideLabelKey         MessageId = "PasswdLengthSlideLabel"
-	PasswdGenCardTitleKey             MessageId = "PasswdGenCardTitle"
-	HistoryCardTitleKey               MessageId = "HistoryCardTitle"
-	NumberCheckLabelKey               MessageId = "NumberCheckLabel"
-	LowercaseCheckLabelKey            MessageId = "LowercaseCheckLabel"
-	UppercaseCheckLabelKey            MessageId = "UppercaseCheckLabel"
-	DuplicateCheckLabelKey            MessageId = "DuplicateCheckLabel"
-	CopyButtonLabelKey                MessageId = "CopyButtonLabel"
-	GenerateButtonLabelKey            MessageId = "GenerateButtonLabel"
-	ResetButtonLabelKey               MessageId = "ResetButtonLabel"
-	HistoryCheckLabelKey              MessageId = "HistoryCheckLabel"
-	IncludeSpecialCharSetFormLabelKey MessageId = "IncludeSpecialCharSetFormLabel"
-	ExcludeSpecialCharSetFormLabelKey MessageId = "ExcludeSpecialCharSetFormLabel"
+	MainWindowTitleKey                     MessageId = "MainWindowTitle"
+	PassWdTabTitleKey                      MessageId = "PassWdTabTitle"
+	SettingTabTitleKey                     MessageId = "SettingTabTitle"
+	SettingAppearanceCardTitleKey          MessageId = "SettingAppearanceCardTitle"
+	SettingThemeFormTitleKey               MessageId = "SettingThemeFormTitle"
+	SettingLangFormTitleKey                MessageId = "SettingLangFormTitle"
+	PasswdStrengthLabelKey                 MessageId = "PasswdStrengthLabel"
+	PasswdLengthLabelKey                   MessageId = "PasswdLengthLabel"
+	PasswdLengthSlideLabelKey              MessageId = "PasswdLengthSlideLabel"
+	PasswdGenCardTitleKey                  MessageId = "PasswdGenCardTitle"
+	HistoryCardTitleKey                    MessageId = "HistoryCardTitle"
+	NumberCheckLabelKey                    MessageId = "NumberCheckLabel"
+	LowercaseCheckLabelKey                 MessageId = "LowercaseCheckLabel"
+	UppercaseCheckLabelKey                 MessageId = "UppercaseCheckLabel"
+	DuplicateCheckLabelKey                 MessageId = "DuplicateCheckLabel"
+	CopyButtonLabelKey                     MessageId = "CopyButtonLabel"
+	GenerateButtonLabelKey                 MessageId = "GenerateButtonLabel"
+	ResetButtonLabelKey                    MessageId = "ResetButtonLabel"
+	HistoryCheckLabelKey                   MessageId = "HistoryCheckLabel"
+	IncludeSpecialCharSetFormLabelKey      MessageId = "IncludeSpecialCharSetFormLabel"
+	ExcludeSpecialCharSetFormLabelKey      MessageId = "ExcludeSpecialCharSetFormLabel"
+	PeekButtonLabelKey                     MessageId = "PeekButtonLabel"
+	AlwaysMaskCheckLabelKey                MessageId = "AlwaysMaskCheckLabel"
+	SettingPrivacyCardTitleKey             MessageId = "SettingPrivacyCardTitle"
+	SaveDefaultButtonLabelKey              MessageId = "SaveDefaultButtonLabel"
+	FactoryResetButtonLabelKey             MessageId = "FactoryResetButtonLabel"
+	SettingResetCardTitleKey               MessageId = "SettingResetCardTitle"
+	FactoryResetConfirmTitleKey            MessageId = "FactoryResetConfirmTitle"
+	FactoryResetConfirmMessageKey          MessageId = "FactoryResetConfirmMessage"
+	ConfirmButtonLabelKey                  MessageId = "ConfirmButtonLabel"
+	CancelButtonLabelKey                   MessageId = "CancelButtonLabel"
+	ProfileFormLabelKey                    MessageId = "ProfileFormLabel"
+	ProfileSelectPlaceholderKey            MessageId = "ProfileSelectPlaceholder"
+	SettingProfileCardTitleKey             MessageId = "SettingProfileCardTitle"
+	ProfileNameDialogTitleKey              MessageId = "ProfileNameDialogTitle"
+	ProfileNameFormLabelKey                MessageId = "ProfileNameFormLabel"
+	ProfileSaveAsButtonLabelKey            MessageId = "ProfileSaveAsButtonLabel"
+	ProfileRenameButtonLabelKey            MessageId = "ProfileRenameButtonLabel"
+	ProfileDuplicateButtonLabelKey         MessageId = "ProfileDuplicateButtonLabel"
+	ProfileDeleteButtonLabelKey            MessageId = "ProfileDeleteButtonLabel"
+	ProfileDeleteConfirmMessageKey         MessageId = "ProfileDeleteConfirmMessage"
+	ProfileImportButtonLabelKey            MessageId = "ProfileImportButtonLabel"
+	ProfileExportButtonLabelKey            MessageId = "ProfileExportButtonLabel"
+	HashPanelTitleKey                      MessageId = "HashPanelTitle"
+	HashComputeButtonLabelKey              MessageId = "HashComputeButtonLabel"
+	DeriveTabTitleKey                      MessageId = "DeriveTabTitle"
+	DeriveCardTitleKey                     MessageId = "DeriveCardTitle"
+	DeriveMasterFormLabelKey               MessageId = "DeriveMasterFormLabel"
+	DeriveSiteFormLabelKey                 MessageId = "DeriveSiteFormLabel"
+	DeriveLoginFormLabelKey                MessageId = "DeriveLoginFormLabel"
+	DeriveCounterFormLabelKey              MessageId = "DeriveCounterFormLabel"
+	DeriveButtonLabelKey                   MessageId = "DeriveButtonLabel"
+	SharePanelTitleKey                     MessageId = "SharePanelTitle"
+	ShareSplitButtonLabelKey               MessageId = "ShareSplitButtonLabel"
+	ShareCombineButtonLabelKey             MessageId = "ShareCombineButtonLabel"
+	ShareCountFormLabelKey                 MessageId = "ShareCountFormLabel"
+	ShareThresholdFormLabelKey             MessageId = "ShareThresholdFormLabel"
+	ShareListDialogTitleKey                MessageId = "ShareListDialogTitle"
+	ShareCopyAllButtonLabelKey             MessageId = "ShareCopyAllButtonLabel"
+	ShareExportButtonLabelKey              MessageId = "ShareExportButtonLabel"
+	ShareCombineFormLabelKey               MessageId = "ShareCombineFormLabel"
+	ShareSecretFormLabelKey                MessageId = "ShareSecretFormLabel"
+	WifiTabTitleKey                        MessageId = "WifiTabTitle"
+	WifiCardTitleKey                       MessageId = "WifiCardTitle"
+	WifiSSIDFormLabelKey                   MessageId = "WifiSSIDFormLabel"
+	WifiHiddenCheckLabelKey                MessageId = "WifiHiddenCheckLabel"
+	WifiPassphraseFormLabelKey             MessageId = "WifiPassphraseFormLabel"
+	QRExportPNGButtonLabelKey              MessageId = "QRExportPNGButtonLabel"
+	QRExportSVGButtonLabelKey              MessageId = "QRExportSVGButtonLabel"
+	QRButtonLabelKey                       MessageId = "QRButtonLabel"
+	QRDialogTitleKey                       MessageId = "QRDialogTitle"
+	PhoneticPanelTitleKey                  MessageId = "PhoneticPanelTitle"
+	PhoneticUppercaseKey                   MessageId = "PhoneticUppercase"
+	PhoneticLowercaseKey                   MessageId = "PhoneticLowercase"
+	PhoneticDigitZeroKey                   MessageId = "PhoneticDigitZero"
+	PhoneticDigitOneKey                    MessageId = "PhoneticDigitOne"
+	PhoneticDigitTwoKey                    MessageId = "PhoneticDigitTwo"
+	PhoneticDigitThreeKey                  MessageId = "PhoneticDigitThree"
+	PhoneticDigitFourKey                   MessageId = "PhoneticDigitFour"
+	PhoneticDigitFiveKey                   MessageId = "PhoneticDigitFive"
+	PhoneticDigitSixKey                    MessageId = "PhoneticDigitSix"
+	PhoneticDigitSevenKey                  MessageId = "PhoneticDigitSeven"
+	PhoneticDigitEightKey                  MessageId = "PhoneticDigitEight"
+	PhoneticDigitNineKey                   MessageId = "PhoneticDigitNine"
+	PhoneticSymbolSpaceKey                 MessageId = "PhoneticSymbolSpace"
+	PhoneticSymbolExclamationKey           MessageId = "PhoneticSymbolExclamation"
+	PhoneticSymbolQuoteKey                 MessageId = "PhoneticSymbolQuote"
+	PhoneticSymbolHashKey                  MessageId = "PhoneticSymbolHash"
+	PhoneticSymbolDollarKey                MessageId = "PhoneticSymbolDollar"
+	PhoneticSymbolPercentKey               MessageId = "PhoneticSymbolPercent"
+	PhoneticSymbolAmpersandKey             MessageId = "PhoneticSymbolAmpersand"
+	PhoneticSymbolApostropheKey            MessageId = "PhoneticSymbolApostrophe"
+	PhoneticSymbolLeftParenKey             MessageId = "PhoneticSymbolLeftParen"
+	PhoneticSymbolRightParenKey            MessageId = "PhoneticSymbolRightParen"
+	PhoneticSymbolAsteriskKey              MessageId = "PhoneticSymbolAsterisk"
+	PhoneticSymbolPlusKey                  MessageId = "PhoneticSymbolPlus"
+	PhoneticSymbolCommaKey                 MessageId = "PhoneticSymbolComma"
+	PhoneticSymbolHyphenKey                MessageId = "PhoneticSymbolHyphen"
+	PhoneticSymbolPeriodKey                MessageId = "PhoneticSymbolPeriod"
+	PhoneticSymbolSlashKey                 MessageId = "PhoneticSymbolSlash"
+	PhoneticSymbolColonKey                 MessageId = "PhoneticSymbolColon"
+	PhoneticSymbolSemicolonKey             MessageId = "PhoneticSymbolSemicolon"
+	PhoneticSymbolLessThanKey              MessageId = "PhoneticSymbolLessThan"
+	PhoneticSymbolEqualsKey                MessageId = "PhoneticSymbolEquals"
+	PhoneticSymbolGreaterThanKey           MessageId = "PhoneticSymbolGreaterThan"
+	PhoneticSymbolQuestionKey              MessageId = "PhoneticSymbolQuestion"
+	PhoneticSymbolAtKey                    MessageId = "PhoneticSymbolAt"
+	PhoneticSymbolLeftBracketKey           MessageId = "PhoneticSymbolLeftBracket"
+	PhoneticSymbolBackslashKey             MessageId = "PhoneticSymbolBackslash"
+	PhoneticSymbolRightBracketKey          MessageId = "PhoneticSymbolRightBracket"
+	PhoneticSymbolCaretKey                 MessageId = "PhoneticSymbolCaret"
+	PhoneticSymbolUnderscoreKey            MessageId = "PhoneticSymbolUnderscore"
+	PhoneticSymbolBacktickKey              MessageId = "PhoneticSymbolBacktick"
+	PhoneticSymbolLeftBraceKey             MessageId = "PhoneticSymbolLeftBrace"
+	PhoneticSymbolPipeKey                  MessageId = "PhoneticSymbolPipe"
+	PhoneticSymbolRightBraceKey            MessageId = "PhoneticSymbolRightBrace"
+	PhoneticSymbolTildeKey                 MessageId = "PhoneticSymbolTilde"
+	ChunkSizeFormLabelKey                  MessageId = "ChunkSizeFormLabel"
+	CharClassLatin1CheckLabelKey           MessageId = "CharClassLatin1CheckLabel"
+	CharClassCyrillicCheckLabelKey         MessageId = "CharClassCyrillicCheckLabel"
+	CharClassGreekCheckLabelKey            MessageId = "CharClassGreekCheckLabel"
+	CharClassHexCheckLabelKey              MessageId = "CharClassHexCheckLabel"
+	CharClassBase58CheckLabelKey           MessageId = "CharClassBase58CheckLabel"
+	CharClassEmojiCheckLabelKey            MessageId = "CharClassEmojiCheckLabel"
+	PassphraseTabTitleKey                  MessageId = "PassphraseTabTitle"
+	PassphraseCardTitleKey                 MessageId = "PassphraseCardTitle"
+	PassphraseWordListFormLabelKey         MessageId = "PassphraseWordListFormLabel"
+	PassphraseFollowLangCheckLabelKey      MessageId = "PassphraseFollowLangCheckLabel"
+	PassphraseWordsFormLabelKey            MessageId = "PassphraseWordsFormLabel"
+	PassphraseDigitsFormLabelKey           MessageId = "PassphraseDigitsFormLabel"
+	PassphraseSeparatorFormLabelKey        MessageId = "PassphraseSeparatorFormLabel"
+	PassphraseCapitalizeCheckLabelKey      MessageId = "PassphraseCapitalizeCheckLabel"
+	PassphraseFormLabelKey                 MessageId = "PassphraseFormLabel"
+	StrengthUnknownInfoKey                 MessageId = "StrengthUnknownInfo"
+	StrengthVeryWeakInfoKey                MessageId = "StrengthVeryWeakInfo"
+	StrengthWeakInfoKey                    MessageId = "StrengthWeakInfo"
+	StrengthNormalInfoKey                  MessageId = "StrengthNormalInfo"
+	StrengthStrongInfoKey                  MessageId = "StrengthStrongInfo"
+	StrengthVeryStrongInfoKey              MessageId = "StrengthVeryStrongInfo"
+	StrengthVeryWeakCostKey                MessageId = "StrengthVeryWeakCost"
+	StrengthWeakCostKey                    MessageId = "StrengthWeakCost"
+	StrengthNormalCostKey                  MessageId = "StrengthNormalCost"
+	StrengthStrongCostKey                  MessageId = "StrengthStrongCost"
+	StrengthVeryStrongCostKey              MessageId = "StrengthVeryStrongCost"
+	ErrorInvalidLengthMessageKey           MessageId = "ErrorInvalidLengthMessage"
+	ErrorInvalidCharsetMessageKey          MessageId = "ErrorInvalidCharsetMessage"
+	ErrorInvalidOptionsMessageKey          MessageId = "ErrorInvalidOptionsMessage"
+	ErrorBuildCharsetMessageKey            MessageId = "ErrorBuildCharsetMessage"
+	ErrorInvalidPolicyMessageKey           MessageId = "ErrorInvalidPolicyMessage"
+	ErrorPolicyUnsatisfiedMessageKey       MessageId = "ErrorPolicyUnsatisfiedMessage"
+	ErrorInvalidCharClassMessageKey        MessageId = "ErrorInvalidCharClassMessage"
+	ErrorInvalidTokenFormatMessageKey      MessageId = "ErrorInvalidTokenFormatMessage"
+	ErrorInvalidTokenBytesMessageKey       MessageId = "ErrorInvalidTokenBytesMessage"
+	ErrorInvalidRecoveryCodeConfMessageKey MessageId = "ErrorInvalidRecoveryCodeConfMessage"
+	ErrorRecoveryCodeSpaceMessageKey       MessageId = "ErrorRecoveryCodeSpaceMessage"
+	ErrorInvalidDeriveInputMessageKey      MessageId = "ErrorInvalidDeriveInputMessage"
+	ErrorDeriveUnsatisfiedMessageKey       MessageId = "ErrorDeriveUnsatisfiedMessage"
+	ErrorInvalidWifiLengthMessageKey       MessageId = "ErrorInvalidWifiLengthMessage"
+	ErrorInvalidWifiCharsetMessageKey      MessageId = "ErrorInvalidWifiCharsetMessage"
+	ErrorInvalidSSIDMessageKey             MessageId = "ErrorInvalidSSIDMessage"
+	ErrorInvalidPassphraseWordsMessageKey  MessageId = "ErrorInvalidPassphraseWordsMessage"
+	ErrorInvalidPassphraseDigitsMessageKey MessageId = "ErrorInvalidPassphraseDigitsMessage"
+	HistoryCountLabelKey                   MessageId = "HistoryCountLabel"
+	ThemePreviewFormLabelKey               MessageId = "ThemePreviewFormLabel"
+	ThemeImportButtonLabelKey              MessageId = "ThemeImportButtonLabel"
+	ThemeExportButtonLabelKey              MessageId = "ThemeExportButtonLabel"
+	SettingFontFormLabelKey                MessageId = "SettingFontFormLabel"
+	FontBuiltinLabelKey                    MessageId = "FontBuiltinLabel"
+	FontChooseButtonLabelKey               MessageId = "FontChooseButtonLabel"
+	FontResetButtonLabelKey                MessageId = "FontResetButtonLabel"
+	FontScaleFormLabelKey                  MessageId = "FontScaleFormLabel"
+	ErrorInvalidShareParamsMessageKey      MessageId = "ErrorInvalidShareParamsMessage"
+	ErrorInvalidSecretMessageKey           MessageId = "ErrorInvalidSecretMessage"
+	ErrorInvalidShareMessageKey            MessageId = "ErrorInvalidShareMessage"
+	ErrorShareChecksumMismatchMessageKey   MessageId = "ErrorShareChecksumMismatchMessage"
+	ErrorMixedSharesMessageKey             MessageId = "ErrorMixedSharesMessage"
+	ErrorDuplicateShareMessageKey          MessageId = "ErrorDuplicateShareMessage"
+	ErrorNotEnoughSharesMessageKey         MessageId = "ErrorNotEnoughSharesMessage"
+	ErrorSecretDigestMismatchMessageKey    MessageId = "ErrorSecretDigestMismatchMessage"
+	ErrorInvalidHashFormatMessageKey       MessageId = "ErrorInvalidHashFormatMessage"
+	ErrorInvalidHashParamsMessageKey       MessageId = "ErrorInvalidHashParamsMessage"
+	ErrorProfileNotFoundMessageKey         MessageId = "ErrorProfileNotFoundMessage"
+	ErrorProfileAlreadyExistsMessageKey    MessageId = "ErrorProfileAlreadyExistsMessage"
+	ErrorInvalidProfileNameMessageKey      MessageId = "ErrorInvalidProfileNameMessage"
+	ErrorInvalidProfileModeMessageKey      MessageId = "ErrorInvalidProfileModeMessage"
+	ErrorInvalidQRFormatMessageKey         MessageId = "ErrorInvalidQRFormatMessage"
+	ErrorInvalidSheetFormatMessageKey      MessageId = "ErrorInvalidSheetFormatMessage"
+	ErrorWordListNotFoundMessageKey        MessageId = "ErrorWordListNotFoundMessage"
+	ErrorThemeNotFoundMessageKey           MessageId = "ErrorThemeNotFoundMessage"
+	ErrorUnsupportedSheetCharsMessageKey   MessageId = "ErrorUnsupportedSheetCharsMessage"
+	ShareSheetPageHeaderKey                MessageId = "ShareSheetPageHeader"
+	ErrorNonLoopbackAddrMessageKey         MessageId = "ErrorNonLoopbackAddrMessage"
+	SharePrintButtonLabelKey               MessageId = "SharePrintButtonLabel"
+	ErrorInvalidShareCountMessageKey       MessageId = "ErrorInvalidShareCountMessage"
+	ErrorPrintUnavailableMessageKey        MessageId = "ErrorPrintUnavailableMessage"
+	ErrorPrintFailedMessageKey             MessageId = "ErrorPrintFailedMessage"
+	ProfileOverwriteConfirmMessageKey      MessageId = "ProfileOverwriteConfirmMessage"
+	ProfileImportedMessageKey              MessageId = "ProfileImportedMessage"
+	ThemeDarkNameKey                       MessageId = "ThemeDarkName"
+	ThemeLightNameKey                      MessageId = "ThemeLightName"
+	ThemeSystemNameKey                     MessageId = "ThemeSystemName"
 )
+
+// MessageIds 所有消息ID,i18n check 按此检查语言包
+var MessageIds = []MessageId{
+	MainWindowTitleKey,
+	PassWdTabTitleKey,
+	SettingTabTitleKey,
+	SettingAppearanceCardTitleKey,
+	SettingThemeFormTitleKey,
+	SettingLangFormTitleKey,
+	PasswdStrengthLabelKey,
+	PasswdLengthLabelKey,
+	PasswdLengthSlideLabelKey,
+	PasswdGenCardTitleKey,
+	HistoryCardTitleKey,
+	NumberCheckLabelKey,
+	LowercaseCheckLabelKey,
+	UppercaseCheckLabelKey,
+	DuplicateCheckLabelKey,
+	CopyButtonLabelKey,
+	GenerateButtonLabelKey,
+	ResetButtonLabelKey,
+	HistoryCheckLabelKey,
+	IncludeSpecialCharSetFormLabelKey,
+	ExcludeSpecialCharSetFormLabelKey,
+	PeekButtonLabelKey,
+	AlwaysMaskCheckLabelKey,
+	SettingPrivacyCardTitleKey,
+	SaveDefaultButtonLabelKey,
+	FactoryResetButtonLabelKey,
+	SettingResetCardTitleKey,
+	FactoryResetConfirmTitleKey,
+	FactoryResetConfirmMessageKey,
+	ConfirmButtonLabelKey,
+	CancelButtonLabelKey,
+	ProfileFormLabelKey,
+	ProfileSelectPlaceholderKey,
+	SettingProfileCardTitleKey,
+	ProfileNameDialogTitleKey,
+	ProfileNameFormLabelKey,
+	ProfileSaveAsButtonLabelKey,
+	ProfileRenameButtonLabelKey,
+	ProfileDuplicateButtonLabelKey,
+	ProfileDeleteButtonLabelKey,
+	ProfileDeleteConfirmMessageKey,
+	ProfileImportButtonLabelKey,
+	ProfileExportButtonLabelKey,
+	HashPanelTitleKey,
+	HashComputeButtonLabelKey,
+	DeriveTabTitleKey,
+	DeriveCardTitleKey,
+	DeriveMasterFormLabelKey,
+	DeriveSiteFormLabelKey,
+	DeriveLoginFormLabelKey,
+	DeriveCounterFormLabelKey,
+	DeriveButtonLabelKey,
+	SharePanelTitleKey,
+	ShareSplitButtonLabelKey,
+	ShareCombineButtonLabelKey,
+	ShareCountFormLabelKey,
+	ShareThresholdFormLabelKey,
+	ShareListDialogTitleKey,
+	ShareCopyAllButtonLabelKey,
+	ShareExportButtonLabelKey,
+	ShareCombineFormLabelKey,
+	ShareSecretFormLabelKey,
+	WifiTabTitleKey,
+	WifiCardTitleKey,
+	WifiSSIDFormLabelKey,
+	WifiHiddenCheckLabelKey,
+	WifiPassphraseFormLabelKey,
+	QRExportPNGButtonLabelKey,
+	QRExportSVGButtonLabelKey,
+	QRButtonLabelKey,
+	QRDialogTitleKey,
+	PhoneticPanelTitleKey,
+	PhoneticUppercaseKey,
+	PhoneticLowercaseKey,
+	PhoneticDigitZeroKey,
+	PhoneticDigitOneKey,
+	PhoneticDigitTwoKey,
+	PhoneticDigitThreeKey,
+	PhoneticDigitFourKey,
+	PhoneticDigitFiveKey,
+	PhoneticDigitSixKey,
+	PhoneticDigitSevenKey,
+	PhoneticDigitEightKey,
+	PhoneticDigitNineKey,
+	PhoneticSymbolSpaceKey,
+	PhoneticSymbolExclamationKey,
+	PhoneticSymbolQuoteKey,
+	PhoneticSymbolHashKey,
+	PhoneticSymbolDollarKey,
+	PhoneticSymbolPercentKey,
+	PhoneticSymbolAmpersandKey,
+	PhoneticSymbolApostropheKey,
+	PhoneticSymbolLeftParenKey,
+	PhoneticSymbolRightParenKey,
+	PhoneticSymbolAsteriskKey,
+	PhoneticSymbolPlusKey,
+	PhoneticSymbolCommaKey,
+	PhoneticSymbolHyphenKey,
+	PhoneticSymbolPeriodKey,
+	PhoneticSymbolSlashKey,
+	PhoneticSymbolColonKey,
+	PhoneticSymbolSemicolonKey,
+	PhoneticSymbolLessThanKey,
+	PhoneticSymbolEqualsKey,
+	PhoneticSymbolGreaterThanKey,
+	PhoneticSymbolQuestionKey,
+	PhoneticSymbolAtKey,
+	PhoneticSymbolLeftBracketKey,
+	PhoneticSymbolBackslashKey,
+	PhoneticSymbolRightBracketKey,
+	PhoneticSymbolCaretKey,
+	PhoneticSymbolUnderscoreKey,
+	PhoneticSymbolBacktickKey,
+	PhoneticSymbolLeftBraceKey,
+	PhoneticSymbolPipeKey,
+	PhoneticSymbolRightBraceKey,
+	PhoneticSymbolTildeKey,
+	ChunkSizeFormLabelKey,
+	CharClassLatin1CheckLabelKey,
+	CharClassCyrillicCheckLabelKey,
+	CharClassGreekCheckLabelKey,
+	CharClassHexCheckLabelKey,
+	CharClassBase58CheckLabelKey,
+	CharClassEmojiCheckLabelKey,
+	PassphraseTabTitleKey,
+	PassphraseCardTitleKey,
+	PassphraseWordListFormLabelKey,
+	PassphraseFollowLangCheckLabelKey,
+	PassphraseWordsFormLabelKey,
+	PassphraseDigitsFormLabelKey,
+	PassphraseSeparatorFormLabelKey,
+	PassphraseCapitalizeCheckLabelKey,
+	PassphraseFormLabelKey,
+	StrengthUnknownInfoKey,
+	StrengthVeryWeakInfoKey,
+	StrengthWeakInfoKey,
+	StrengthNormalInfoKey,
+	StrengthStrongInfoKey,
+	StrengthVeryStrongInfoKey,
+	StrengthVeryWeakCostKey,
+	StrengthWeakCostKey,
+	StrengthNormalCostKey,
+	StrengthStrongCostKey,
+	StrengthVeryStrongCostKey,
+	ErrorInvalidLengthMessageKey,
+	ErrorInvalidCharsetMessageKey,
+	ErrorInvalidOptionsMessageKey,
+	ErrorBuildCharsetMessageKey,
+	ErrorInvalidPolicyMessageKey,
+	ErrorPolicyUnsatisfiedMessageKey,
+	ErrorInvalidCharClassMessageKey,
+	ErrorInvalidTokenFormatMessageKey,
+	ErrorInvalidTokenBytesMessageKey,
+	ErrorInvalidRecoveryCodeConfMessageKey,
+	ErrorRecoveryCodeSpaceMessageKey,
+	ErrorInvalidDeriveInputMessageKey,
+	ErrorDeriveUnsatisfiedMessageKey,
+	ErrorInvalidWifiLengthMessageKey,
+	ErrorInvalidWifiCharsetMessageKey,
+	ErrorInvalidSSIDMessageKey,
+	ErrorInvalidPassphraseWordsMessageKey,
+	ErrorInvalidPassphraseDigitsMessageKey,
+	HistoryCountLabelKey,
+	ThemePreviewFormLabelKey,
+	ThemeImportButtonLabelKey,
+	ThemeExportButtonLabelKey,
+	SettingFontFormLabelKey,
+	FontBuiltinLabelKey,
+	FontChooseButtonLabelKey,
+	FontResetButtonLabelKey,
+	FontScaleFormLabelKey,
+	ErrorInvalidShareParamsMessageKey,
+	ErrorInvalidSecretMessageKey,
+	ErrorInvalidShareMessageKey,
+	ErrorShareChecksumMismatchMessageKey,
+	ErrorMixedSharesMessageKey,
+	ErrorDuplicateShareMessageKey,
+	ErrorNotEnoughSharesMessageKey,
+	ErrorSecretDigestMismatchMessageKey,
+	ErrorInvalidHashFormatMessageKey,
+	ErrorInvalidHashParamsMessageKey,
+	ErrorProfileNotFoundMessageKey,
+	ErrorProfileAlreadyExistsMessageKey,
+	ErrorInvalidProfileNameMessageKey,
+	ErrorInvalidProfileModeMessageKey,
+	ErrorInvalidQRFormatMessageKey,
+	ErrorInvalidSheetFormatMessageKey,
+	ErrorWordListNotFoundMessageKey,
+	ErrorThemeNotFoundMessageKey,
+	ErrorUnsupportedSheetCharsMessageKey,
+	ShareSheetPageHeaderKey,
+	ErrorNonLoopbackAddrMessageKey,
+	SharePrintButtonLabelKey,
+	ErrorInvalidShareCountMessageKey,
+	ErrorPrintUnavailableMessageKey,
+	ErrorPrintFailedMessageKey,
+	ProfileOverwriteConfirmMessageKey,
+	ProfileImportedMessageKey,
+	ThemeDarkNameKey,
+	ThemeLightNameKey,
+	ThemeSystemNameKey,
+}
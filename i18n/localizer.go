@@ -0,0 +1,174 @@
+package i18n
+
+import (
+	"fmt"
+	"fyne.io/fyne/v2"
+	goi18n "github.com/nicksnyder/go-i18n/v2/i18n"
+	"sort"
+	"sync"
+)
+
+// Localizer 窗口的界面文本翻译器,切换语言时刷新注册到它的文本。
+// 每个窗口持有自己的 Localizer,窗口或对话框关闭时取消注册的文本,可在多个goroutine中使用
+type Localizer struct {
+	// 保证切换语言依次进行
+	switchMu sync.Mutex
+	mu       sync.Mutex
+	lang     string
+	// 当前语言的翻译器,未选择语言时为nil
+	localizer     *goi18n.Localizer
+	nextId        uint64
+	registrations map[uint64]*Registration
+}
+
+func NewLocalizer() *Localizer {
+	return &Localizer{registrations: make(map[uint64]*Registration)}
+}
+
+// Registration 注册的文本,语言切换或模板参数变化时调用 m 更新显示
+type Registration struct {
+	localizer *Localizer
+	id        uint64
+	messageId MessageId
+	m         func(value string)
+
+	mu sync.Mutex
+	// 模板参数,例如 {{.Count}}
+	templateData map[string]interface{}
+	// 复数形式使用的数量,nil表示不区分单复数
+	pluralCount interface{}
+}
+
+// Register 注册文本,已选择语言时立即按当前语言调用一次 m
+func (l *Localizer) Register(messageId MessageId, m func(value string)) *Registration {
+	return l.RegisterTemplate(messageId, nil, nil, m)
+}
+
+// RegisterTemplate 注册带模板参数和复数数量的文本,例如 "{{.Count}} passwords generated"
+func (l *Localizer) RegisterTemplate(messageId MessageId, templateData map[string]interface{}, pluralCount interface{},
+	m func(value string)) *Registration {
+	l.mu.Lock()
+	l.nextId++
+	r := &Registration{
+		localizer:    l,
+		id:           l.nextId,
+		messageId:    messageId,
+		m:            m,
+		templateData: templateData,
+		pluralCount:  pluralCount,
+	}
+	l.registrations[r.id] = r
+	l.mu.Unlock()
+	r.refresh()
+	return r
+}
+
+// Unregister 取消注册,之后切换语言不再更新该文本
+func (r *Registration) Unregister() {
+	r.localizer.mu.Lock()
+	defer r.localizer.mu.Unlock()
+	delete(r.localizer.registrations, r.id)
+}
+
+// SetTemplateData 更新模板参数和复数数量,并按当前语言重新显示
+func (r *Registration) SetTemplateData(templateData map[string]interface{}, pluralCount interface{}) {
+	r.mu.Lock()
+	r.templateData = templateData
+	r.pluralCount = pluralCount
+	r.mu.Unlock()
+	r.refresh()
+}
+
+// refresh 按当前语言更新文本,当前语言缺失该消息时返回false。
+// 持有 r.mu 直到 m 返回,并发刷新时最后显示的总是最新的语言和参数,因此 m 中不能再调用 SetTemplateData
+func (r *Registration) refresh() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	value, ok, err := r.localizer.localize(r.messageId, r.templateData, r.pluralCount)
+	if !ok {
+		return true
+	}
+	r.m(value)
+	return err == nil
+}
+
+// Localize 按当前语言翻译一次文本,用于导出的文件等不随语言切换刷新的内容
+func (l *Localizer) Localize(messageId MessageId, templateData map[string]interface{}, pluralCount interface{}) string {
+	value, ok, _ := l.localize(messageId, templateData, pluralCount)
+	if !ok {
+		return string(messageId)
+	}
+	return value
+}
+
+// localize 未选择语言时返回false
+func (l *Localizer) localize(messageId MessageId, templateData map[string]interface{},
+	pluralCount interface{}) (string, bool, error) {
+	l.mu.Lock()
+	localizer := l.localizer
+	l.mu.Unlock()
+	if localizer == nil {
+		return "", false, nil
+	}
+	// 当前语言缺失的消息使用英文,英文也缺失时显示消息ID
+	value, err := localizer.Localize(&goi18n.LocalizeConfig{
+		MessageID:    string(messageId),
+		TemplateData: templateData,
+		PluralCount:  pluralCount,
+	})
+	if value == "" {
+		value = string(messageId)
+	}
+	return value, true, err
+}
+
+// Lang 返回当前语言,未选择语言时为空
+func (l *Localizer) Lang() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lang
+}
+
+// SwitchLang 切换界面语言并刷新注册的文本和控件。
+// 语言无法解析或语言包加载失败时不切换;缺失的消息使用英文显示,并返回 *MissingMessagesError
+func (l *Localizer) SwitchLang(lang string, canvasObjectsToRefresh []fyne.CanvasObject) error {
+	langTag, err := parseLang(lang)
+	if err != nil {
+		return fmt.Errorf("parse language %q failed: %w", lang, err)
+	}
+	bundle, err := defaultBundle()
+	if err != nil {
+		return err
+	}
+	l.switchMu.Lock()
+	defer l.switchMu.Unlock()
+	l.mu.Lock()
+	l.lang = lang
+	l.localizer = goi18n.NewLocalizer(bundle, langTag.String())
+	registrations := make([]*Registration, 0, len(l.registrations))
+	for _, r := range l.registrations {
+		registrations = append(registrations, r)
+	}
+	l.mu.Unlock()
+	// 回调中可能注册或取消注册文本,不持有锁
+	missing := make(map[MessageId]bool)
+	for _, r := range registrations {
+		if !r.refresh() {
+			missing[r.messageId] = true
+		}
+	}
+	for _, co := range canvasObjectsToRefresh {
+		co.Refresh()
+	}
+	if len(missing) > 0 {
+		messageIds := make([]MessageId, 0, len(missing))
+		for messageId := range missing {
+			messageIds = append(messageIds, messageId)
+		}
+		sort.Slice(messageIds, func(i, j int) bool {
+			return messageIds[i] < messageIds[j]
+		})
+		return &MissingMessagesError{Lang: lang, MessageIds: messageIds}
+	}
+	return nil
+}
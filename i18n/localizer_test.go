@@ -0,0 +1,54 @@
+package i18n
+
+import "testing"
+
+func TestLocalizerUnregister(t *testing.T) {
+	l := NewLocalizer()
+	var kept, released string
+	l.Register(ConfirmButtonLabelKey, func(value string) {
+		kept = value
+	})
+	r := l.Register(ConfirmButtonLabelKey, func(value string) {
+		released = value
+	})
+	if err := l.SwitchLang("en", nil); err != nil {
+		t.Fatalf("SwitchLang(en) error = %v", err)
+	}
+	english := kept
+	if english == "" || released != english {
+		t.Fatalf("after en: kept = %q, released = %q", kept, released)
+	}
+	r.Unregister()
+	if err := l.SwitchLang("zh", nil); err != nil {
+		t.Fatalf("SwitchLang(zh) error = %v", err)
+	}
+	if kept == english {
+		t.Errorf("registered text not refreshed: %q", kept)
+	}
+	if released != english {
+		t.Errorf("unregistered text refreshed to %q", released)
+	}
+}
+
+func TestLocalizerLocalize(t *testing.T) {
+	l := NewLocalizer()
+	if got := l.Localize(HistoryCountLabelKey, nil, nil); got != string(HistoryCountLabelKey) {
+		t.Errorf("Localize() before SwitchLang = %q, want message id", got)
+	}
+	if err := l.SwitchLang("en", nil); err != nil {
+		t.Fatalf("SwitchLang(en) error = %v", err)
+	}
+	tests := []struct {
+		count int
+		want  string
+	}{
+		{1, "1 password generated"},
+		{3, "3 passwords generated"},
+	}
+	for _, tt := range tests {
+		got := l.Localize(HistoryCountLabelKey, map[string]interface{}{"Count": tt.count}, tt.count)
+		if got != tt.want {
+			t.Errorf("Localize(%d) = %q, want %q", tt.count, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package i18n_test
+
+import (
+	"passwdgen/i18n"
+	"passwdgen/theme"
+	"testing"
+)
+
+// TestBuiltinThemeNames 内置主题都要有翻译后的显示名称,旧版本保存的名称对应到内置主题
+func TestBuiltinThemeNames(t *testing.T) {
+	reg := theme.NewRegistry()
+	builtin := 0
+	for _, name := range reg.Names() {
+		p, err := reg.Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		messageId, ok := i18n.ThemeNameMessageId(name)
+		if p.Source != theme.SourceBuiltin {
+			if ok {
+				t.Errorf("theme %q is not builtin but has message %q", name, messageId)
+			}
+			continue
+		}
+		builtin++
+		if !ok {
+			t.Errorf("builtin theme %q has no message", name)
+			continue
+		}
+		for _, lang := range []string{"en", "zh"} {
+			if value, err := i18n.Localize(lang, messageId); err != nil || value == "" {
+				t.Errorf("Localize(%q, %q) = %q, %v", lang, messageId, value, err)
+			}
+		}
+	}
+	if builtin != 3 {
+		t.Errorf("found %d builtin themes, want 3", builtin)
+	}
+	for legacy, want := range map[string]string{
+		"Dark(暗黑)":     theme.DarkTheme,
+		"Light(白色)":    theme.LightTheme,
+		"System(跟随系统)": theme.SystemTheme,
+		"Nord":         "Nord",
+	} {
+		if got := theme.CanonicalName(legacy); got != want {
+			t.Errorf("CanonicalName(%q) = %q, want %q", legacy, got, want)
+		}
+		if _, err := reg.Get(theme.CanonicalName(legacy)); err != nil {
+			t.Errorf("Get(CanonicalName(%q)) error = %v", legacy, err)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package main
 import (
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
+	"os"
+	"passwdgen/cli"
 	"passwdgen/theme"
 	"passwdgen/ui"
 )
@@ -10,6 +12,10 @@ import (
 const AppId = "passwdgen"
 
 func main() {
+	// 带参数时以命令行模式运行
+	if len(os.Args) > 1 {
+		os.Exit(cli.Run(os.Args[1:]))
+	}
 	application := app.NewWithID(AppId)
 	application.Preferences().SetBool("__MainWindowInit__", false)
 	mainWindow := ui.InitMainWindow()
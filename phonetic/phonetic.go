@@ -0,0 +1,116 @@
+package phonetic
+
+import (
+	"fmt"
+	"passwdgen/i18n"
+	"strings"
+)
+
+// natoAlphabet 北约音标字母,各语言通用不做翻译
+var natoAlphabet = []string{
+	"Alfa", "Bravo", "Charlie", "Delta", "Echo", "Foxtrot", "Golf", "Hotel", "India", "Juliett", "Kilo", "Lima",
+	"Mike", "November", "Oscar", "Papa", "Quebec", "Romeo", "Sierra", "Tango", "Uniform", "Victor", "Whiskey",
+	"X-ray", "Yankee", "Zulu",
+}
+
+var digitKeys = []i18n.MessageId{
+	i18n.PhoneticDigitZeroKey,
+	i18n.PhoneticDigitOneKey,
+	i18n.PhoneticDigitTwoKey,
+	i18n.PhoneticDigitThreeKey,
+	i18n.PhoneticDigitFourKey,
+	i18n.PhoneticDigitFiveKey,
+	i18n.PhoneticDigitSixKey,
+	i18n.PhoneticDigitSevenKey,
+	i18n.PhoneticDigitEightKey,
+	i18n.PhoneticDigitNineKey,
+}
+
+// symbolKeys 可打印ASCII符号的读法
+var symbolKeys = map[rune]i18n.MessageId{
+	' ':  i18n.PhoneticSymbolSpaceKey,
+	'!':  i18n.PhoneticSymbolExclamationKey,
+	'"':  i18n.PhoneticSymbolQuoteKey,
+	'#':  i18n.PhoneticSymbolHashKey,
+	'$':  i18n.PhoneticSymbolDollarKey,
+	'%':  i18n.PhoneticSymbolPercentKey,
+	'&':  i18n.PhoneticSymbolAmpersandKey,
+	'\'': i18n.PhoneticSymbolApostropheKey,
+	'(':  i18n.PhoneticSymbolLeftParenKey,
+	')':  i18n.PhoneticSymbolRightParenKey,
+	'*':  i18n.PhoneticSymbolAsteriskKey,
+	'+':  i18n.PhoneticSymbolPlusKey,
+	',':  i18n.PhoneticSymbolCommaKey,
+	'-':  i18n.PhoneticSymbolHyphenKey,
+	'.':  i18n.PhoneticSymbolPeriodKey,
+	'/':  i18n.PhoneticSymbolSlashKey,
+	':':  i18n.PhoneticSymbolColonKey,
+	';':  i18n.PhoneticSymbolSemicolonKey,
+	'<':  i18n.PhoneticSymbolLessThanKey,
+	'=':  i18n.PhoneticSymbolEqualsKey,
+	'>':  i18n.PhoneticSymbolGreaterThanKey,
+	'?':  i18n.PhoneticSymbolQuestionKey,
+	'@':  i18n.PhoneticSymbolAtKey,
+	'[':  i18n.PhoneticSymbolLeftBracketKey,
+	'\\': i18n.PhoneticSymbolBackslashKey,
+	']':  i18n.PhoneticSymbolRightBracketKey,
+	'^':  i18n.PhoneticSymbolCaretKey,
+	'_':  i18n.PhoneticSymbolUnderscoreKey,
+	'`':  i18n.PhoneticSymbolBacktickKey,
+	'{':  i18n.PhoneticSymbolLeftBraceKey,
+	'|':  i18n.PhoneticSymbolPipeKey,
+	'}':  i18n.PhoneticSymbolRightBraceKey,
+	'~':  i18n.PhoneticSymbolTildeKey,
+}
+
+// Localize 返回消息的当前语言文本
+type Localize func(messageId i18n.MessageId) string
+
+// Token 单个字符及其读法
+type Token struct {
+	Char     string
+	Spelling string
+}
+
+// Keys 返回读法用到的所有消息,用于注册语言切换
+func Keys() []i18n.MessageId {
+	keys := []i18n.MessageId{i18n.PhoneticUppercaseKey, i18n.PhoneticLowercaseKey}
+	keys = append(keys, digitKeys...)
+	for _, key := range symbolKeys {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Spell 逐字符返回读法:字母为大小写加北约音标,数字和符号为本地化名称,其它字符为Unicode码点
+func Spell(passwd string, localize Localize) []Token {
+	tokens := make([]Token, 0, len(passwd))
+	for _, r := range passwd {
+		tokens = append(tokens, Token{Char: string(r), Spelling: spellRune(r, localize)})
+	}
+	return tokens
+}
+
+func spellRune(r rune, localize Localize) string {
+	switch {
+	case r >= 'a' && r <= 'z':
+		return localize(i18n.PhoneticLowercaseKey) + " " + natoAlphabet[r-'a']
+	case r >= 'A' && r <= 'Z':
+		return localize(i18n.PhoneticUppercaseKey) + " " + natoAlphabet[r-'A']
+	case r >= '0' && r <= '9':
+		return localize(digitKeys[r-'0'])
+	}
+	if key, ok := symbolKeys[r]; ok {
+		return localize(key)
+	}
+	return fmt.Sprintf("U+%04X", r)
+}
+
+// Format 每行一个字符: 字符<TAB>读法
+func Format(tokens []Token) string {
+	lines := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		lines = append(lines, token.Char+"\t"+token.Spelling)
+	}
+	return strings.Join(lines, "\n")
+}
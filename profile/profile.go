@@ -0,0 +1,436 @@
+package profile
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"io"
+	"os"
+	"passwdgen/errcode"
+	"passwdgen/gen"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	AppDirName       = "passwdgen"
+	ProfilesFileName = "profiles.toml"
+)
+
+const (
+	ErrorProfileNotFound    errcode.Code = "profile_not_found"
+	ErrorProfileExists      errcode.Code = "profile_already_exists"
+	ErrorInvalidProfileName errcode.Code = "invalid_profile_name"
+	ErrorInvalidProfileMode errcode.Code = "invalid_profile_mode"
+)
+
+// 登记错误码对应的提示消息
+func init() {
+	errcode.Register(ErrorProfileNotFound, "ErrorProfileNotFoundMessage")
+	errcode.Register(ErrorProfileExists, "ErrorProfileAlreadyExistsMessage")
+	errcode.Register(ErrorInvalidProfileName, "ErrorInvalidProfileNameMessage")
+	errcode.Register(ErrorInvalidProfileMode, "ErrorInvalidProfileModeMessage")
+}
+
+var ProfileNotFoundError = errcode.New(ErrorProfileNotFound)
+var ProfileExistsError = errcode.New(ErrorProfileExists)
+var invalidProfileNameError = errcode.New(ErrorInvalidProfileName)
+var invalidProfileModeError = errcode.New(ErrorInvalidProfileMode)
+
+// Mode 配置的生成方式
+type Mode string
+
+const (
+	// ModePassword 按 Conf 生成随机密码,设置了 Policy 时满足策略
+	ModePassword Mode = "password"
+	// ModePassphrase 按 Passphrase 从词表生成口令
+	ModePassphrase Mode = "passphrase"
+)
+
+// Modes 所有生成方式
+var Modes = []Mode{ModePassword, ModePassphrase}
+
+// Profile 命名的密码生成配置
+type Profile struct {
+	Name string             `toml:"name"`
+	Mode Mode               `toml:"mode"`
+	Conf *gen.PasswdGenConf `toml:"conf"`
+	// 密码需满足的各类字符最少数量,nil表示不限制
+	Policy *gen.PasswdPolicy `toml:"policy,omitempty"`
+	// 口令配置,nil时使用默认值
+	Passphrase *gen.PassphraseConf `toml:"passphrase,omitempty"`
+}
+
+type profileFile struct {
+	Profiles []*Profile `toml:"profile"`
+}
+
+type rawProfileFile struct {
+	Profiles []struct {
+		Name       string            `toml:"name"`
+		Mode       Mode              `toml:"mode"`
+		Conf       toml.Primitive    `toml:"conf"`
+		Policy     *gen.PasswdPolicy `toml:"policy"`
+		Passphrase *toml.Primitive   `toml:"passphrase"`
+	} `toml:"profile"`
+}
+
+// Store 配置存储,GUI和CLI共用同一个文件
+type Store struct {
+	path      string
+	mu        sync.Mutex
+	profiles  []*Profile
+	listeners []func()
+}
+
+// DefaultPath 返回默认的配置文件路径
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, AppDirName, ProfilesFileName), nil
+}
+
+// Open 打开指定路径的配置存储,文件不存在时返回空存储
+func Open(path string) (*Store, error) {
+	store := &Store{path: path}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+	profiles, err := decode(f)
+	if err != nil {
+		return nil, err
+	}
+	store.profiles = profiles
+	return store, nil
+}
+
+// OpenDefault 打开默认路径的配置存储
+func OpenDefault() (*Store, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return Open(path)
+}
+
+// AddChangeListener 注册配置变动监听器
+func (s *Store) AddChangeListener(listener func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, listener)
+}
+
+// Names 返回排序后的配置名称
+func (s *Store) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.profiles))
+	for _, p := range s.profiles {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get 按名称获取配置的副本
+func (s *Store) Get(name string) (*Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.indexOf(name)
+	if i < 0 {
+		return nil, ProfileNotFoundError
+	}
+	return s.profiles[i].clone(), nil
+}
+
+// Save 新建或覆盖指定名称配置的密码生成配置,已有配置的生成方式和策略保持不变
+func (s *Store) Save(name string, conf *gen.PasswdGenConf) error {
+	name, err := normalizeName(name)
+	if err != nil {
+		return err
+	}
+	c := cloneConf(conf)
+	return s.update(func() error {
+		if i := s.indexOf(name); i >= 0 {
+			s.profiles[i].Conf = c
+		} else {
+			s.profiles = append(s.profiles, &Profile{Name: name, Mode: ModePassword, Conf: c})
+		}
+		return nil
+	})
+}
+
+// Rename 重命名配置
+func (s *Store) Rename(oldName, newName string) error {
+	newName, err := normalizeName(newName)
+	if err != nil {
+		return err
+	}
+	return s.update(func() error {
+		i := s.indexOf(oldName)
+		if i < 0 {
+			return ProfileNotFoundError
+		}
+		if oldName == newName {
+			return nil
+		}
+		if s.indexOf(newName) >= 0 {
+			return ProfileExistsError
+		}
+		s.profiles[i].Name = newName
+		return nil
+	})
+}
+
+// Duplicate 以新名称复制配置
+func (s *Store) Duplicate(name, newName string) error {
+	newName, err := normalizeName(newName)
+	if err != nil {
+		return err
+	}
+	return s.update(func() error {
+		i := s.indexOf(name)
+		if i < 0 {
+			return ProfileNotFoundError
+		}
+		if s.indexOf(newName) >= 0 {
+			return ProfileExistsError
+		}
+		p := s.profiles[i].clone()
+		p.Name = newName
+		s.profiles = append(s.profiles, p)
+		return nil
+	})
+}
+
+// Delete 删除配置
+func (s *Store) Delete(name string) error {
+	return s.update(func() error {
+		i := s.indexOf(name)
+		if i < 0 {
+			return ProfileNotFoundError
+		}
+		s.profiles = append(s.profiles[:i], s.profiles[i+1:]...)
+		return nil
+	})
+}
+
+// Export 以TOML格式导出指定名称的配置,未指定名称时导出全部
+func (s *Store) Export(w io.Writer, names ...string) error {
+	s.mu.Lock()
+	var profiles []*Profile
+	if len(names) == 0 {
+		profiles = s.profiles
+	} else {
+		for _, name := range names {
+			i := s.indexOf(name)
+			if i < 0 {
+				s.mu.Unlock()
+				return ProfileNotFoundError
+			}
+			profiles = append(profiles, s.profiles[i])
+		}
+	}
+	err := encode(w, profiles)
+	s.mu.Unlock()
+	return err
+}
+
+// Import 导入TOML格式的配置,返回导入的配置名称和其中覆盖的已有配置名称。
+// 每个配置都需能生成密码,否则不导入任何配置;overwrite 为假且有同名配置时也不导入,
+// 返回同名的配置名称和 ProfileExistsError,确认后再以 overwrite 为真导入
+func (s *Store) Import(r io.Reader, overwrite bool) (names, overwritten []string, err error) {
+	profiles, err := decode(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, p := range profiles {
+		if err := p.Validate(); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", p.Name, err)
+		}
+	}
+	err = s.update(func() error {
+		for _, p := range profiles {
+			if s.indexOf(p.Name) >= 0 {
+				overwritten = append(overwritten, p.Name)
+			}
+		}
+		if len(overwritten) > 0 && !overwrite {
+			return ProfileExistsError
+		}
+		for _, p := range profiles {
+			if i := s.indexOf(p.Name); i >= 0 {
+				s.profiles[i] = p
+			} else {
+				s.profiles = append(s.profiles, p)
+			}
+			names = append(names, p.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, overwritten, err
+	}
+	return names, overwritten, nil
+}
+
+// Validate 检查配置能否按生成方式生成密码,设置了策略时检查策略能否满足。
+// 在副本上生成,不改变配置
+func (p *Profile) Validate() error {
+	c := p.clone()
+	var err error
+	switch {
+	case c.Mode == ModePassphrase:
+		_, err = gen.GeneratePassphrase(c.Passphrase)
+	case c.Policy != nil:
+		_, err = gen.GeneratePasswordWithPolicy(c.Conf, c.Policy)
+	default:
+		_, err = gen.GeneratePassword(c.Conf)
+	}
+	return err
+}
+
+// update 在锁内修改配置并写回文件,失败时回滚
+func (s *Store) update(m func() error) error {
+	s.mu.Lock()
+	backup := make([]*Profile, 0, len(s.profiles))
+	for _, p := range s.profiles {
+		backup = append(backup, p.clone())
+	}
+	err := m()
+	if err == nil {
+		err = s.flush()
+	}
+	if err != nil {
+		s.profiles = backup
+		s.mu.Unlock()
+		return err
+	}
+	listeners := s.listeners
+	s.mu.Unlock()
+	for _, listener := range listeners {
+		listener()
+	}
+	return nil
+}
+
+func (s *Store) flush() error {
+	var buf bytes.Buffer
+	if err := encode(&buf, s.profiles); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func (s *Store) indexOf(name string) int {
+	for i, p := range s.profiles {
+		if p.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (p *Profile) clone() *Profile {
+	c := &Profile{Name: p.Name, Mode: p.Mode}
+	if p.Conf != nil {
+		c.Conf = cloneConf(p.Conf)
+	}
+	if p.Policy != nil {
+		policy := *p.Policy
+		c.Policy = &policy
+	}
+	if p.Passphrase != nil {
+		passphrase := *p.Passphrase
+		c.Passphrase = &passphrase
+	}
+	return c
+}
+
+// cloneConf 复制密码生成配置,包括字符类切片
+func cloneConf(conf *gen.PasswdGenConf) *gen.PasswdGenConf {
+	c := *conf
+	if conf.Classes != nil {
+		c.Classes = append([]string(nil), conf.Classes...)
+	}
+	return &c
+}
+
+func encode(w io.Writer, profiles []*Profile) error {
+	return toml.NewEncoder(w).Encode(&profileFile{Profiles: profiles})
+}
+
+func decode(r io.Reader) ([]*Profile, error) {
+	var raw rawProfileFile
+	md, err := toml.NewDecoder(r).Decode(&raw)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]struct{}, len(raw.Profiles))
+	profiles := make([]*Profile, 0, len(raw.Profiles))
+	for _, rp := range raw.Profiles {
+		name, err := normalizeName(rp.Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := seen[name]; ok {
+			return nil, ProfileExistsError
+		}
+		seen[name] = struct{}{}
+		// 旧版本的配置没有生成方式,视为随机密码
+		mode := rp.Mode
+		if mode == "" {
+			mode = ModePassword
+		}
+		if !validMode(mode) {
+			return nil, invalidProfileModeError
+		}
+		// 缺失的配置项使用默认值
+		conf := gen.NewDefaultPasswdGenConf()
+		if err := md.PrimitiveDecode(rp.Conf, conf); err != nil {
+			return nil, err
+		}
+		p := &Profile{Name: name, Mode: mode, Conf: conf, Policy: rp.Policy}
+		if rp.Passphrase != nil {
+			p.Passphrase = gen.NewDefaultPassphraseConf()
+			if err := md.PrimitiveDecode(*rp.Passphrase, p.Passphrase); err != nil {
+				return nil, err
+			}
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, nil
+}
+
+func validMode(mode Mode) bool {
+	for _, m := range Modes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", invalidProfileNameError
+	}
+	return name, nil
+}
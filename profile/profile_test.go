@@ -0,0 +1,266 @@
+package profile
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"passwdgen/gen"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newTestStore(t *testing.T, names ...string) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), AppDirName, ProfilesFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		if err := s.Save(name, gen.NewDefaultPasswdGenConf()); err != nil {
+			t.Fatalf("Save(%q) error = %v", name, err)
+		}
+	}
+	return s
+}
+
+func TestStoreErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		op   func(s *Store) error
+		err  error
+	}{
+		{"rename to existing", func(s *Store) error { return s.Rename("work", "home") }, ProfileExistsError},
+		{"rename missing", func(s *Store) error { return s.Rename("bank", "shop") }, ProfileNotFoundError},
+		{"rename to blank", func(s *Store) error { return s.Rename("work", "  ") }, invalidProfileNameError},
+		{"duplicate to existing", func(s *Store) error { return s.Duplicate("work", " home ") }, ProfileExistsError},
+		{"duplicate missing", func(s *Store) error { return s.Duplicate("bank", "shop") }, ProfileNotFoundError},
+		{"delete missing", func(s *Store) error { return s.Delete("bank") }, ProfileNotFoundError},
+		{"save blank", func(s *Store) error { return s.Save("", gen.NewDefaultPasswdGenConf()) }, invalidProfileNameError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t, "work", "home")
+			if err := tt.op(s); !errors.Is(err, tt.err) {
+				t.Fatalf("error = %v, want %v", err, tt.err)
+			}
+			// 失败的操作不改变配置
+			if names := s.Names(); !reflect.DeepEqual(names, []string{"home", "work"}) {
+				t.Errorf("Names() = %v", names)
+			}
+		})
+	}
+}
+
+func TestStoreRenameDuplicateDelete(t *testing.T) {
+	s := newTestStore(t, "work")
+	if err := s.Rename("work", "office"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Duplicate("office", "home"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("office"); err != nil {
+		t.Fatal(err)
+	}
+	reopened, err := Open(s.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := reopened.Names(); !reflect.DeepEqual(names, []string{"home"}) {
+		t.Errorf("Names() = %v", names)
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src := newTestStore(t)
+	input := `
+[[profile]]
+name = "pin"
+mode = "password"
+[profile.conf]
+length = 8
+enable_number = true
+[profile.policy]
+min_number = 2
+
+[[profile]]
+name = "words"
+mode = "passphrase"
+[profile.conf]
+length = 16
+[profile.passphrase]
+words = 4
+separator = "."
+capitalize = true
+digits = 2
+
+[[profile]]
+name = "cyrillic"
+[profile.conf]
+length = 20
+enable_lowercase = true
+classes = ["cyrillic", "emoji"]
+[profile.policy]
+min_special = 1
+`
+	if _, _, err := src.Import(strings.NewReader(input), false); err != nil {
+		t.Fatal(err)
+	}
+	var exported bytes.Buffer
+	if err := src.Export(&exported); err != nil {
+		t.Fatal(err)
+	}
+	dst := newTestStore(t)
+	names, overwritten, err := dst.Import(bytes.NewReader(exported.Bytes()), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(names, []string{"pin", "words", "cyrillic"}) || len(overwritten) != 0 {
+		t.Errorf("Import() = %v, %v", names, overwritten)
+	}
+	var reexported bytes.Buffer
+	if err := dst.Export(&reexported); err != nil {
+		t.Fatal(err)
+	}
+	if exported.String() != reexported.String() {
+		t.Errorf("round trip changed profiles:\n%s\nwant:\n%s", reexported.String(), exported.String())
+	}
+	words, err := dst.Get("words")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &gen.PassphraseConf{Words: 4, Separator: ".", Capitalize: true, Digits: 2}
+	if words.Mode != ModePassphrase || !reflect.DeepEqual(words.Passphrase, want) {
+		t.Errorf("words = %+v, passphrase %+v", words, words.Passphrase)
+	}
+}
+
+func TestImportLegacy(t *testing.T) {
+	// 旧版本导出的文件没有生成方式,配置项也可能不全
+	input := `
+[[profile]]
+name = "old"
+[profile.conf]
+length = 12
+`
+	s := newTestStore(t)
+	if _, _, err := s.Import(strings.NewReader(input), false); err != nil {
+		t.Fatal(err)
+	}
+	p, err := s.Get("old")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Mode != ModePassword {
+		t.Errorf("Mode = %q, want %q", p.Mode, ModePassword)
+	}
+	want := gen.NewDefaultPasswdGenConf()
+	want.Length = 12
+	if !reflect.DeepEqual(p.Conf, want) {
+		t.Errorf("Conf = %+v, want %+v", p.Conf, want)
+	}
+}
+
+func TestImportInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"unknown mode", "[[profile]]\nname = \"a\"\nmode = \"pin\"\n[profile.conf]\nlength = 8\n"},
+		{"blank name", "[[profile]]\nname = \" \"\n[profile.conf]\nlength = 8\n"},
+		{"duplicate names", "[[profile]]\nname = \"a\"\n[profile.conf]\n[[profile]]\nname = \"a\"\n[profile.conf]\n"},
+		{"no characters", "[[profile]]\nname = \"a\"\n[profile.conf]\nenable_number = false\nenable_lowercase = false\n" +
+			"enable_uppercase = false\ninclude_special_charset = \"\"\n"},
+		{"policy too long", "[[profile]]\nname = \"a\"\n[profile.conf]\nlength = 4\n[profile.policy]\nmin_number = 5\n"},
+		{"policy without class", "[[profile]]\nname = \"a\"\n[profile.conf]\nenable_uppercase = false\n" +
+			"[profile.policy]\nmin_uppercase = 1\n"},
+		{"passphrase words", "[[profile]]\nname = \"a\"\nmode = \"passphrase\"\n[profile.conf]\n[profile.passphrase]\nwords = 1\n"},
+		{"missing word list", "[[profile]]\nname = \"a\"\nmode = \"passphrase\"\n[profile.conf]\n" +
+			"[profile.passphrase]\nword_list = \"klingon\"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t, "work")
+			// 有效的配置和无效的配置一起导入时都不导入
+			input := "[[profile]]\nname = \"valid\"\n[profile.conf]\nlength = 8\n\n" + tt.input
+			if _, _, err := s.Import(strings.NewReader(input), true); err == nil {
+				t.Fatal("Import() succeeded")
+			}
+			if names := s.Names(); !reflect.DeepEqual(names, []string{"work"}) {
+				t.Errorf("Names() = %v", names)
+			}
+		})
+	}
+}
+
+func TestImportOverwrite(t *testing.T) {
+	s := newTestStore(t, "work", "home")
+	input := "[[profile]]\nname = \"work\"\n[profile.conf]\nlength = 30\n\n[[profile]]\nname = \"new\"\n[profile.conf]\n"
+	names, overwritten, err := s.Import(strings.NewReader(input), false)
+	if !errors.Is(err, ProfileExistsError) {
+		t.Fatalf("error = %v, want %v", err, ProfileExistsError)
+	}
+	if names != nil || !reflect.DeepEqual(overwritten, []string{"work"}) {
+		t.Errorf("Import() = %v, %v", names, overwritten)
+	}
+	// 未确认覆盖时不导入任何配置
+	if got := s.Names(); !reflect.DeepEqual(got, []string{"home", "work"}) {
+		t.Errorf("Names() = %v", got)
+	}
+	names, overwritten, err = s.Import(strings.NewReader(input), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(names, []string{"work", "new"}) || !reflect.DeepEqual(overwritten, []string{"work"}) {
+		t.Errorf("Import() = %v, %v", names, overwritten)
+	}
+	work, err := s.Get("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if work.Conf.Length != 30 {
+		t.Errorf("Length = %d, want 30", work.Conf.Length)
+	}
+}
+
+func TestUpdateRollback(t *testing.T) {
+	s := newTestStore(t, "work")
+	// 临时文件的位置是目录,写回文件失败
+	if err := os.Mkdir(s.path+".tmp", 0o700); err != nil {
+		t.Fatal(err)
+	}
+	listened := false
+	s.AddChangeListener(func() {
+		listened = true
+	})
+	ops := map[string]func() error{
+		"save":      func() error { return s.Save("home", gen.NewDefaultPasswdGenConf()) },
+		"rename":    func() error { return s.Rename("work", "office") },
+		"duplicate": func() error { return s.Duplicate("work", "copy") },
+		"delete":    func() error { return s.Delete("work") },
+		"import": func() error {
+			_, _, err := s.Import(strings.NewReader("[[profile]]\nname = \"new\"\n[profile.conf]\n"), true)
+			return err
+		},
+	}
+	for name, op := range ops {
+		if err := op(); err == nil {
+			t.Errorf("%s succeeded", name)
+		}
+		if names := s.Names(); !reflect.DeepEqual(names, []string{"work"}) {
+			t.Errorf("%s: Names() = %v", name, names)
+		}
+	}
+	if listened {
+		t.Error("listener called for failed updates")
+	}
+	reopened, err := Open(s.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := reopened.Names(); !reflect.DeepEqual(names, []string{"work"}) {
+		t.Errorf("file Names() = %v", names)
+	}
+}
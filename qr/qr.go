@@ -0,0 +1,105 @@
+package qr
+
+import (
+	"bufio"
+	"fmt"
+	qrcode "github.com/skip2/go-qrcode"
+	"image"
+	"image/png"
+	"io"
+	"passwdgen/errcode"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatPNG Format = "png"
+	FormatSVG Format = "svg"
+)
+
+// Formats 所有支持的图片格式
+var Formats = []Format{FormatPNG, FormatSVG}
+
+const (
+	// DefaultPNGSize PNG图片的默认边长(像素)
+	DefaultPNGSize = 512
+	// svgModuleSize SVG中每个模块的边长
+	svgModuleSize = 8
+)
+
+// ErrorInvalidFormat 不支持的二维码图片格式
+const ErrorInvalidFormat errcode.Code = "invalid_qr_format"
+
+// 登记错误码对应的提示消息
+func init() {
+	errcode.Register(ErrorInvalidFormat, "ErrorInvalidQRFormatMessage")
+}
+
+var invalidFormatError = errcode.New(ErrorInvalidFormat)
+
+// Code 二维码,使用中等纠错级别
+type Code struct {
+	qr *qrcode.QRCode
+}
+
+// Encode 编码二维码
+func Encode(content string) (*Code, error) {
+	q, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return nil, err
+	}
+	return &Code{qr: q}, nil
+}
+
+// ParseFormat 解析图片格式名称
+func ParseFormat(format string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(format) {
+			return f, nil
+		}
+	}
+	return "", invalidFormatError
+}
+
+// Image 返回指定边长的图片,包含四周的空白区
+func (c *Code) Image(size int) image.Image {
+	return c.qr.Image(size)
+}
+
+// Write 按指定格式写出图片
+func (c *Code) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatPNG:
+		return png.Encode(w, c.Image(DefaultPNGSize))
+	case FormatSVG:
+		return c.WriteSVG(w)
+	}
+	return invalidFormatError
+}
+
+// WriteSVG 写出SVG,相邻的深色模块合并为一个矩形
+func (c *Code) WriteSVG(w io.Writer) error {
+	bitmap := c.qr.Bitmap()
+	size := len(bitmap) * svgModuleSize
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n",
+		size, size, len(bitmap), len(bitmap))
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", len(bitmap), len(bitmap))
+	bw.WriteString(`<path fill="#000000" d="`)
+	for y, row := range bitmap {
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			fmt.Fprintf(bw, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+	bw.WriteString("\"/>\n</svg>\n")
+	return bw.Flush()
+}
@@ -0,0 +1,43 @@
+package qr
+
+import (
+	"github.com/makiuchi-d/gozxing"
+	gozxingqr "github.com/makiuchi-d/gozxing/qrcode"
+	"passwdgen/gen"
+	"testing"
+)
+
+// decode 解码图片中的二维码
+func decode(t *testing.T, code *Code, size int) string {
+	t.Helper()
+	bmp, err := gozxing.NewBinaryBitmapFromImage(code.Image(size))
+	if err != nil {
+		t.Fatalf("new binary bitmap failed: %v", err)
+	}
+	result, err := gozxingqr.NewQRCodeReader().Decode(bmp, nil)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	return result.GetText()
+}
+
+func TestImageRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"password", `Kx7#pQ2!vZ9&mW4$`},
+		{"wifi", gen.WifiPayload(`My;Net,"5G"`, `s3cr\et:pass`, true)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Encode(tt.content)
+			if err != nil {
+				t.Fatalf("Encode(%q) failed: %v", tt.content, err)
+			}
+			if got := decode(t, code, DefaultPNGSize); got != tt.content {
+				t.Errorf("decoded %q, want %q", got, tt.content)
+			}
+		})
+	}
+}
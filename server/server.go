@@ -0,0 +1,450 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"passwdgen/errcode"
+	"passwdgen/gen"
+	"passwdgen/i18n"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultAddr         = "127.0.0.1:8787"
+	DefaultMaxBodyBytes = 16 << 10
+	DefaultRate         = 10
+	DefaultBurst        = 20
+	// MaxCount 批量生成的最大数量
+	MaxCount = 1000
+	// ResponseLanguage 响应中强度说明和错误提示的语言
+	ResponseLanguage = "en"
+)
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+// ErrorNonLoopbackAddr 监听地址不是本机地址
+const ErrorNonLoopbackAddr errcode.Code = "non_loopback_addr"
+
+// 登记错误码对应的提示消息
+func init() {
+	errcode.Register(ErrorNonLoopbackAddr, "ErrorNonLoopbackAddrMessage")
+}
+
+var nonLoopbackAddrError = errcode.New(ErrorNonLoopbackAddr)
+
+// Options 服务配置
+type Options struct {
+	// 监听的TCP地址,只允许本机地址
+	Addr string
+	// Unix socket路径,设置后忽略Addr
+	Socket string
+	// Bearer token,为空时不校验
+	Token string
+	// 请求体最大字节数
+	MaxBodyBytes int64
+	// 每秒允许的请求数
+	Rate float64
+	// 突发请求数
+	Burst int
+	// 请求中未指定的生成配置使用的默认值
+	Defaults *gen.PasswdGenConf
+}
+
+// Server 本地HTTP/JSON密码生成服务
+type Server struct {
+	opts    Options
+	limiter *rateLimiter
+	handler http.Handler
+}
+
+func New(opts Options) *Server {
+	if opts.Addr == "" {
+		opts.Addr = DefaultAddr
+	}
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	if opts.Rate <= 0 {
+		opts.Rate = DefaultRate
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = DefaultBurst
+	}
+	if opts.Defaults == nil {
+		opts.Defaults = gen.NewDefaultPasswdGenConf()
+	}
+	s := &Server{opts: opts, limiter: newRateLimiter(opts.Rate, opts.Burst)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/password", s.post(s.handlePassword))
+	mux.HandleFunc("/v1/passwords", s.post(s.handlePasswords))
+	mux.HandleFunc("/v1/policy/password", s.post(s.handlePolicyPassword))
+	mux.HandleFunc("/v1/strength", s.post(s.handleStrength))
+	mux.HandleFunc("/v1/openapi.json", s.handleOpenAPI)
+	s.handler = s.middleware(mux)
+	return s
+}
+
+// Handler 返回服务的http.Handler
+func (s *Server) Handler() http.Handler {
+	return s.handler
+}
+
+// Listen 按配置监听本机地址或Unix socket
+func (s *Server) Listen() (net.Listener, error) {
+	if s.opts.Socket != "" {
+		// 清理上次遗留的socket文件
+		if fi, err := os.Lstat(s.opts.Socket); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			_ = os.Remove(s.opts.Socket)
+		}
+		l, err := net.Listen("unix", s.opts.Socket)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.Chmod(s.opts.Socket, 0o600); err != nil {
+			_ = l.Close()
+			return nil, err
+		}
+		return l, nil
+	}
+	host, _, err := net.SplitHostPort(s.opts.Addr)
+	if err != nil {
+		return nil, err
+	}
+	if host != "localhost" {
+		ip := net.ParseIP(host)
+		if ip == nil || !ip.IsLoopback() {
+			return nil, nonLoopbackAddrError
+		}
+	}
+	return net.Listen("tcp", s.opts.Addr)
+}
+
+// Serve 在监听器上提供服务,ctx取消时优雅退出
+func (s *Server) Serve(ctx context.Context, l net.Listener) error {
+	srv := &http.Server{
+		Handler:           s.handler,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		MaxHeaderBytes:    8 << 10,
+		// 不记录任何请求和响应内容
+		ErrorLog: log.New(io.Discard, "", 0),
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(l)
+	}()
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		if err == http.ErrServerClosed {
+			return nil
+		}
+		return err
+	}
+}
+
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		header.Set("Cache-Control", "no-store")
+		header.Set("Pragma", "no-cache")
+		header.Set("X-Content-Type-Options", "nosniff")
+		// 先限流再校验token,未授权的请求同样计入限流,避免被用来猜测token
+		if !s.limiter.allow() {
+			header.Set("Retry-After", "1")
+			writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		if s.opts.Token != "" && !s.authorized(r) {
+			header.Set("WWW-Authenticate", `Bearer realm="passwdgen"`)
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, s.opts.MaxBodyBytes)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+	token := strings.TrimPrefix(auth, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) == 1
+}
+
+func (s *Server) post(handler func(r *http.Request) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		resp, err := handler(r)
+		if err != nil {
+			var reqErr *requestError
+			var maxBytesErr *http.MaxBytesError
+			var codeErr *errcode.Error
+			switch {
+			case errors.As(err, &maxBytesErr):
+				writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			case errors.As(err, &reqErr):
+				writeError(w, http.StatusBadRequest, reqErr.Error())
+			case errors.As(err, &codeErr):
+				writeJSON(w, http.StatusUnprocessableEntity, map[string]string{
+					"error": i18n.LocalizeError(ResponseLanguage, err),
+					"code":  string(codeErr.Code),
+				})
+			default:
+				writeError(w, http.StatusUnprocessableEntity, err.Error())
+			}
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	}
+}
+
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPISpec)
+}
+
+// confRequest 请求中的生成配置,未指定的字段使用默认值
+type confRequest struct {
+	Length                *uint8    `json:"length"`
+	EnableNumber          *bool     `json:"enable_number"`
+	EnableLowercase       *bool     `json:"enable_lowercase"`
+	EnableUppercase       *bool     `json:"enable_uppercase"`
+	EnableDuplicate       *bool     `json:"enable_duplicate"`
+	IncludeSpecialCharSet *string   `json:"include_special_charset"`
+	ExcludeSpecialCharSet *string   `json:"exclude_special_charset"`
+	Classes               *[]string `json:"classes"`
+}
+
+func (cr *confRequest) toConf(defaults *gen.PasswdGenConf) *gen.PasswdGenConf {
+	conf := *defaults
+	if cr == nil {
+		return &conf
+	}
+	if cr.Length != nil {
+		conf.Length = *cr.Length
+	}
+	if cr.EnableNumber != nil {
+		conf.EnableNumber = *cr.EnableNumber
+	}
+	if cr.EnableLowercase != nil {
+		conf.EnableLowercase = *cr.EnableLowercase
+	}
+	if cr.EnableUppercase != nil {
+		conf.EnableUppercase = *cr.EnableUppercase
+	}
+	if cr.EnableDuplicate != nil {
+		conf.EnableDuplicate = *cr.EnableDuplicate
+	}
+	if cr.IncludeSpecialCharSet != nil {
+		conf.IncludeSpecialCharSet = *cr.IncludeSpecialCharSet
+	}
+	if cr.ExcludeSpecialCharSet != nil {
+		conf.ExcludeSpecialCharSet = *cr.ExcludeSpecialCharSet
+	}
+	if cr.Classes != nil {
+		conf.Classes = *cr.Classes
+	}
+	return &conf
+}
+
+type passwordsRequest struct {
+	confRequest
+	Count int `json:"count"`
+}
+
+type policyRequest struct {
+	confRequest
+	Policy *gen.PasswdPolicy `json:"policy"`
+	Count  int               `json:"count"`
+}
+
+type strengthRequest struct {
+	Password string `json:"password"`
+}
+
+type passwordResponse struct {
+	Password     string  `json:"password,omitempty"`
+	Strength     float64 `json:"strength"`
+	StrengthInfo string  `json:"strength_info"`
+	CostInfo     string  `json:"cost_info"`
+}
+
+type passwordsResponse struct {
+	Passwords []*passwordResponse `json:"passwords"`
+}
+
+func (s *Server) handlePassword(r *http.Request) (interface{}, error) {
+	var req confRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	result, err := gen.GeneratePassword(req.toConf(s.opts.Defaults))
+	if err != nil {
+		return nil, err
+	}
+	return toResponse(result), nil
+}
+
+func (s *Server) handlePasswords(r *http.Request) (interface{}, error) {
+	var req passwordsRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	conf := req.toConf(s.opts.Defaults)
+	return generateMany(req.Count, func() (*gen.PasswdGenResult, error) {
+		c := *conf
+		return gen.GeneratePassword(&c)
+	})
+}
+
+func (s *Server) handlePolicyPassword(r *http.Request) (interface{}, error) {
+	var req policyRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	if req.Policy == nil {
+		return nil, &requestError{"policy is required"}
+	}
+	conf := req.toConf(s.opts.Defaults)
+	return generateMany(req.Count, func() (*gen.PasswdGenResult, error) {
+		c := *conf
+		return gen.GeneratePasswordWithPolicy(&c, req.Policy)
+	})
+}
+
+func (s *Server) handleStrength(r *http.Request) (interface{}, error) {
+	var req strengthRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	resp := toResponse(gen.AnalyzePassword(req.Password))
+	// 不回显被分析的密码
+	resp.Password = ""
+	return resp, nil
+}
+
+func generateMany(count int, generate func() (*gen.PasswdGenResult, error)) (*passwordsResponse, error) {
+	if count == 0 {
+		count = 1
+	}
+	if count < 0 || count > MaxCount {
+		return nil, &requestError{fmt.Sprintf("count must be between 1 and %d", MaxCount)}
+	}
+	resp := &passwordsResponse{Passwords: make([]*passwordResponse, 0, count)}
+	for i := 0; i < count; i++ {
+		result, err := generate()
+		if err != nil {
+			return nil, err
+		}
+		resp.Passwords = append(resp.Passwords, toResponse(result))
+	}
+	return resp, nil
+}
+
+func toResponse(result *gen.PasswdGenResult) *passwordResponse {
+	resp := &passwordResponse{
+		Password: result.Password,
+		Strength: result.StrengthInt,
+	}
+	resp.StrengthInfo, _ = i18n.Localize(ResponseLanguage, i18n.StrengthMessageId(result.Strength))
+	if messageId, ok := i18n.StrengthCostMessageId(result.Strength); ok {
+		resp.CostInfo, _ = i18n.Localize(ResponseLanguage, messageId)
+	}
+	return resp
+}
+
+// requestError 请求格式错误
+type requestError struct {
+	msg string
+}
+
+func (re *requestError) Error() string {
+	return re.msg
+}
+
+func decodeBody(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		if err == io.EOF {
+			// 空请求体使用默认值
+			return nil
+		}
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return err
+		}
+		return &requestError{fmt.Sprintf("invalid request body: %v", err)}
+	}
+	if decoder.More() {
+		return &requestError{"invalid request body: trailing data"}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+// rateLimiter 令牌桶限流
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	return &rateLimiter{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+func (rl *rateLimiter) allow() bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	now := time.Now()
+	rl.tokens += now.Sub(rl.last).Seconds() * rl.rate
+	if rl.tokens > rl.burst {
+		rl.tokens = rl.burst
+	}
+	rl.last = now
+	if rl.tokens < 1 {
+		return false
+	}
+	rl.tokens--
+	return true
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// do 向服务发送请求,token 为空时不带 Authorization 头
+func do(t *testing.T, h http.Handler, method, path, token, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if got := w.Header().Get("Cache-Control"); got != "no-store" {
+		t.Errorf("%s %s: Cache-Control = %q, want no-store", method, path, got)
+	}
+	return w
+}
+
+func TestAuth(t *testing.T) {
+	h := New(Options{Token: "secret", Rate: 1000, Burst: 1000}).Handler()
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		status int
+	}{
+		{"missing", "", "", http.StatusUnauthorized},
+		{"wrong", "guess", "", http.StatusUnauthorized},
+		{"prefix", "secret-and-more", "", http.StatusUnauthorized},
+		{"basic", "", "Basic c2VjcmV0", http.StatusUnauthorized},
+		{"valid", "secret", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/v1/password", nil)
+			if tt.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if tt.status == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("missing WWW-Authenticate header")
+			}
+			if got := w.Header().Get("Cache-Control"); got != "no-store" {
+				t.Errorf("Cache-Control = %q, want no-store", got)
+			}
+		})
+	}
+}
+
+func TestBodyLimit(t *testing.T) {
+	h := New(Options{MaxBodyBytes: 64}).Handler()
+	body := `{"include_special_charset": "` + strings.Repeat("!", 128) + `"}`
+	if w := do(t, h, http.MethodPost, "/v1/password", "", body); w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusRequestEntityTooLarge, w.Body)
+	}
+	if w := do(t, h, http.MethodPost, "/v1/password", "", `{"length": 16}`); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	h := New(Options{Token: "secret", Rate: 0.001, Burst: 2}).Handler()
+	// 未授权的请求同样计入限流
+	if w := do(t, h, http.MethodPost, "/v1/password", "guess", ""); w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if w := do(t, h, http.MethodPost, "/v1/password", "secret", ""); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+	for _, token := range []string{"secret", "guess"} {
+		w := do(t, h, http.MethodPost, "/v1/password", token, "")
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("token %q: status = %d, want %d", token, w.Code, http.StatusTooManyRequests)
+		}
+		if w.Header().Get("Retry-After") == "" {
+			t.Error("missing Retry-After header")
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	h := New(Options{Rate: 1000, Burst: 1000}).Handler()
+	tests := []struct {
+		body   string
+		status int
+		count  int
+	}{
+		{`{}`, http.StatusOK, 1},
+		{`{"count": 0}`, http.StatusOK, 1},
+		{`{"count": 1}`, http.StatusOK, 1},
+		{`{"count": 5}`, http.StatusOK, 5},
+		{`{"count": 1000}`, http.StatusOK, MaxCount},
+		{`{"count": 1001}`, http.StatusBadRequest, 0},
+		{`{"count": -1}`, http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			w := do(t, h, http.MethodPost, "/v1/passwords", "", tt.body)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var resp passwordsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if len(resp.Passwords) != tt.count {
+				t.Errorf("got %d passwords, want %d", len(resp.Passwords), tt.count)
+			}
+		})
+	}
+}
+
+func TestRequestErrors(t *testing.T) {
+	h := New(Options{Rate: 1000, Burst: 1000}).Handler()
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"unknown field", http.MethodPost, "/v1/password", `{"lenght": 16}`, http.StatusBadRequest},
+		{"nested unknown field", http.MethodPost, "/v1/policy/password", `{"policy": {"bogus": 1}}`, http.StatusBadRequest},
+		{"trailing data", http.MethodPost, "/v1/password", `{} {}`, http.StatusBadRequest},
+		{"malformed", http.MethodPost, "/v1/password", `{`, http.StatusBadRequest},
+		{"missing policy", http.MethodPost, "/v1/policy/password", `{}`, http.StatusBadRequest},
+		{"coded error", http.MethodPost, "/v1/password", `{"length": 0}`, http.StatusUnprocessableEntity},
+		{"method", http.MethodGet, "/v1/password", "", http.StatusMethodNotAllowed},
+		{"openapi method", http.MethodPost, "/v1/openapi.json", "", http.StatusMethodNotAllowed},
+		{"not found", http.MethodPost, "/v1/unknown", "", http.StatusNotFound},
+		{"openapi", http.MethodGet, "/v1/openapi.json", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := do(t, h, tt.method, tt.path, "", tt.body)
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+		})
+	}
+}
+
+func TestCodedErrorLocalized(t *testing.T) {
+	h := New(Options{}).Handler()
+	w := do(t, h, http.MethodPost, "/v1/password", "", `{"length": 0}`)
+	var resp map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp["code"] == "" || resp["error"] == "" || resp["error"] == strings.ReplaceAll(resp["code"], "_", " ") {
+		t.Errorf("response = %v, want a code and a localised message", resp)
+	}
+}
+
+func TestStrengthDoesNotEchoPassword(t *testing.T) {
+	h := New(Options{}).Handler()
+	const password = "correct-horse-battery-staple"
+	w := do(t, h, http.MethodPost, "/v1/strength", "", `{"password": "`+password+`"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body)
+	}
+	if strings.Contains(w.Body.String(), password) {
+		t.Errorf("response echoes the password: %s", w.Body)
+	}
+	var resp passwordResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.StrengthInfo == "" {
+		t.Error("missing strength_info")
+	}
+}
@@ -0,0 +1,79 @@
+package shamir
+
+// GF(2^8) 运算,不可约多项式 x^8+x^4+x^3+x+1 (0x11b),生成元为3
+
+var (
+	expTable [510]byte
+	logTable [256]byte
+)
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		expTable[i] = x
+		expTable[i+255] = x
+		logTable[x] = byte(i)
+		x = gfMulSlow(x, 3)
+	}
+}
+
+func gfMulSlow(a, b byte) byte {
+	var p byte
+	for b > 0 {
+		if b&1 == 1 {
+			p ^= a
+		}
+		carry := a & 0x80
+		a <<= 1
+		if carry != 0 {
+			a ^= 0x1b
+		}
+		b >>= 1
+	}
+	return p
+}
+
+func gfAdd(a, b byte) byte {
+	return a ^ b
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+int(logTable[b])]
+}
+
+// gfDiv b不能为0
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+255-int(logTable[b])]
+}
+
+// evaluate 霍纳法则计算多项式在x处的值,coefficients[0]为常数项
+func evaluate(coefficients []byte, x byte) byte {
+	var y byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = gfAdd(gfMul(y, x), coefficients[i])
+	}
+	return y
+}
+
+// interpolateAtZero 拉格朗日插值计算多项式在0处的值
+func interpolateAtZero(xs, ys []byte) byte {
+	var secret byte
+	for i := range xs {
+		basis := byte(1)
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			// 在0处: x_j / (x_j - x_i),GF(2^8)中减法即加法
+			basis = gfMul(basis, gfDiv(xs[j], gfAdd(xs[j], xs[i])))
+		}
+		secret = gfAdd(secret, gfMul(ys[i], basis))
+	}
+	return secret
+}
@@ -0,0 +1,214 @@
+package shamir
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"passwdgen/errcode"
+	"strconv"
+	"strings"
+)
+
+const (
+	// SharePrefix 分片格式版本前缀
+	SharePrefix = "pgs1"
+	MinShares   = 2
+	MaxShares   = 255
+	MaxSecret   = 1024
+	// 秘密末尾追加的摘要长度,合并时用于校验结果
+	digestLength = 4
+)
+
+const (
+	ErrorInvalidParams    errcode.Code = "invalid_share_params"
+	ErrorInvalidSecret    errcode.Code = "invalid_secret"
+	ErrorInvalidShare     errcode.Code = "invalid_share"
+	ErrorChecksumMismatch errcode.Code = "share_checksum_mismatch"
+	ErrorMixedShares      errcode.Code = "shares_from_different_splits"
+	ErrorDuplicateShare   errcode.Code = "duplicate_share"
+	ErrorNotEnoughShares  errcode.Code = "not_enough_shares"
+	ErrorDigestMismatch   errcode.Code = "recovered_secret_digest_mismatch"
+	// ErrorInvalidShareCount 分片数量或门限不是 MinShares 到 MaxShares 之间的整数
+	ErrorInvalidShareCount errcode.Code = "invalid_share_count"
+)
+
+// 登记错误码对应的提示消息
+func init() {
+	errcode.Register(ErrorInvalidParams, "ErrorInvalidShareParamsMessage")
+	errcode.Register(ErrorInvalidSecret, "ErrorInvalidSecretMessage")
+	errcode.Register(ErrorInvalidShare, "ErrorInvalidShareMessage")
+	errcode.Register(ErrorChecksumMismatch, "ErrorShareChecksumMismatchMessage")
+	errcode.Register(ErrorMixedShares, "ErrorMixedSharesMessage")
+	errcode.Register(ErrorDuplicateShare, "ErrorDuplicateShareMessage")
+	errcode.Register(ErrorNotEnoughShares, "ErrorNotEnoughSharesMessage")
+	errcode.Register(ErrorDigestMismatch, "ErrorSecretDigestMismatchMessage")
+	errcode.Register(ErrorInvalidShareCount, "ErrorInvalidShareCountMessage")
+}
+
+var invalidParamsError = errcode.New(ErrorInvalidParams)
+var invalidSecretError = errcode.New(ErrorInvalidSecret)
+var invalidShareError = errcode.New(ErrorInvalidShare)
+var checksumMismatchError = errcode.New(ErrorChecksumMismatch)
+var mixedSharesError = errcode.New(ErrorMixedShares)
+var duplicateShareError = errcode.New(ErrorDuplicateShare)
+var notEnoughSharesError = errcode.New(ErrorNotEnoughShares)
+var digestMismatchError = errcode.New(ErrorDigestMismatch)
+var invalidShareCountError = errcode.New(ErrorInvalidShareCount)
+
+// Share 秘密分片,文本格式为 pgs1-<组ID>-<门限>-<序号>-<数据>-<校验和>
+type Share struct {
+	// 同一次拆分的分片共享随机组ID,避免混用不同组的分片
+	ID        uint32
+	Threshold byte
+	Index     byte
+	Data      []byte
+}
+
+// ParseShareCount 解析输入的分片数量或门限
+func ParseShareCount(text string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || n < MinShares || n > MaxShares {
+		return 0, invalidShareCountError
+	}
+	return n, nil
+}
+
+// Split 把秘密拆分为n个分片,任意k个分片可以恢复秘密
+func Split(secret []byte, n, k int) ([]*Share, error) {
+	if k < MinShares || n < k || n > MaxShares {
+		return nil, invalidParamsError
+	}
+	if len(secret) == 0 || len(secret) > MaxSecret {
+		return nil, invalidSecretError
+	}
+	digest := sha256.Sum256(secret)
+	payload := append(append([]byte{}, secret...), digest[:digestLength]...)
+	var id [4]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, err
+	}
+	shares := make([]*Share, n)
+	for i := range shares {
+		shares[i] = &Share{
+			ID:        binary.BigEndian.Uint32(id[:]),
+			Threshold: byte(k),
+			Index:     byte(i + 1),
+			Data:      make([]byte, len(payload)),
+		}
+	}
+	// 每个字节使用独立的k-1次随机多项式,常数项为秘密字节
+	coefficients := make([]byte, k)
+	for pos, b := range payload {
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, err
+		}
+		coefficients[0] = b
+		for _, share := range shares {
+			share.Data[pos] = evaluate(coefficients, share.Index)
+		}
+	}
+	return shares, nil
+}
+
+// Combine 使用至少门限数量的分片恢复秘密
+func Combine(shares []*Share) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, notEnoughSharesError
+	}
+	first := shares[0]
+	seen := make(map[byte]struct{}, len(shares))
+	for _, share := range shares {
+		if share.ID != first.ID || share.Threshold != first.Threshold || len(share.Data) != len(first.Data) {
+			return nil, mixedSharesError
+		}
+		if _, ok := seen[share.Index]; ok {
+			return nil, duplicateShareError
+		}
+		seen[share.Index] = struct{}{}
+	}
+	k := int(first.Threshold)
+	if len(shares) < k {
+		return nil, notEnoughSharesError
+	}
+	if len(first.Data) <= digestLength {
+		return nil, invalidShareError
+	}
+	// 多余的分片不参与计算
+	xs := make([]byte, k)
+	ys := make([]byte, k)
+	for i := 0; i < k; i++ {
+		xs[i] = shares[i].Index
+	}
+	payload := make([]byte, len(first.Data))
+	for pos := range payload {
+		for i := 0; i < k; i++ {
+			ys[i] = shares[i].Data[pos]
+		}
+		payload[pos] = interpolateAtZero(xs, ys)
+	}
+	secret, tag := payload[:len(payload)-digestLength], payload[len(payload)-digestLength:]
+	digest := sha256.Sum256(secret)
+	if !bytes.Equal(digest[:digestLength], tag) {
+		return nil, digestMismatchError
+	}
+	return secret, nil
+}
+
+// String 返回分片的文本格式
+func (s *Share) String() string {
+	body := fmt.Sprintf("%s-%08x-%d-%d-%s", SharePrefix, s.ID, s.Threshold, s.Index, hex.EncodeToString(s.Data))
+	return fmt.Sprintf("%s-%08x", body, crc32.ChecksumIEEE([]byte(body)))
+}
+
+// ParseShare 解析文本格式的分片并校验校验和
+func ParseShare(text string) (*Share, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	parts := strings.Split(text, "-")
+	if len(parts) != 6 || parts[0] != SharePrefix {
+		return nil, invalidShareError
+	}
+	sum, err := strconv.ParseUint(parts[5], 16, 32)
+	if err != nil || len(parts[5]) != 8 {
+		return nil, invalidShareError
+	}
+	if crc32.ChecksumIEEE([]byte(text[:len(text)-len(parts[5])-1])) != uint32(sum) {
+		return nil, checksumMismatchError
+	}
+	id, err := strconv.ParseUint(parts[1], 16, 32)
+	if err != nil {
+		return nil, invalidShareError
+	}
+	threshold, err := strconv.ParseUint(parts[2], 10, 8)
+	if err != nil || threshold < MinShares {
+		return nil, invalidShareError
+	}
+	index, err := strconv.ParseUint(parts[3], 10, 8)
+	if err != nil || index == 0 {
+		return nil, invalidShareError
+	}
+	data, err := hex.DecodeString(parts[4])
+	if err != nil || len(data) == 0 {
+		return nil, invalidShareError
+	}
+	return &Share{ID: uint32(id), Threshold: byte(threshold), Index: byte(index), Data: data}, nil
+}
+
+// ParseShares 解析多个分片,忽略空行
+func ParseShares(texts []string) ([]*Share, error) {
+	var shares []*Share
+	for i, text := range texts {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		share, err := ParseShare(text)
+		if err != nil {
+			return nil, fmt.Errorf("share %d: %w", i+1, err)
+		}
+		shares = append(shares, share)
+	}
+	return shares, nil
+}
@@ -0,0 +1,216 @@
+package shamir
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"passwdgen/errcode"
+	"strings"
+	"testing"
+)
+
+// subsets 返回 shares 中所有k个元素的组合
+func subsets(shares []*Share, k int) [][]*Share {
+	if k == 0 {
+		return [][]*Share{nil}
+	}
+	var result [][]*Share
+	for i := 0; i <= len(shares)-k; i++ {
+		for _, rest := range subsets(shares[i+1:], k-1) {
+			result = append(result, append([]*Share{shares[i]}, rest...))
+		}
+	}
+	return result
+}
+
+func wantCode(t *testing.T, err error, code errcode.Code) {
+	t.Helper()
+	var codeErr *errcode.Error
+	if !errors.As(err, &codeErr) || codeErr.Code != code {
+		t.Fatalf("error = %v, want %s", err, code)
+	}
+}
+
+func TestSplitCombine(t *testing.T) {
+	secret := []byte("correct horse battery staple")
+	tests := []struct{ n, k int }{{2, 2}, {3, 2}, {5, 3}, {6, 6}, {7, 4}}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d-of-%d", tt.k, tt.n), func(t *testing.T) {
+			shares, err := Split(secret, tt.n, tt.k)
+			if err != nil {
+				t.Fatalf("Split() error = %v", err)
+			}
+			if len(shares) != tt.n {
+				t.Fatalf("Split() returned %d shares, want %d", len(shares), tt.n)
+			}
+			// 任意k个分片都能恢复秘密,顺序无关
+			for _, subset := range subsets(shares, tt.k) {
+				got, err := Combine(subset)
+				if err != nil {
+					t.Fatalf("Combine(%v) error = %v", subset, err)
+				}
+				if !bytes.Equal(got, secret) {
+					t.Fatalf("Combine() = %q, want %q", got, secret)
+				}
+				reversed := make([]*Share, len(subset))
+				for i, share := range subset {
+					reversed[len(subset)-1-i] = share
+				}
+				if got, err := Combine(reversed); err != nil || !bytes.Equal(got, secret) {
+					t.Fatalf("Combine(reversed) = %q, %v", got, err)
+				}
+			}
+			// 全部分片同样可以恢复
+			if got, err := Combine(shares); err != nil || !bytes.Equal(got, secret) {
+				t.Fatalf("Combine(all) = %q, %v", got, err)
+			}
+			// k-1个分片不足以恢复
+			for _, subset := range subsets(shares, tt.k-1) {
+				_, err := Combine(subset)
+				wantCode(t, err, ErrorNotEnoughShares)
+			}
+		})
+	}
+}
+
+// TestForgedShareDetected 被篡改的分片参与合并时摘要校验失败,不会返回错误的秘密
+func TestForgedShareDetected(t *testing.T) {
+	shares, err := Split([]byte("secret"), 3, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := *shares[2]
+	forged.Data = append([]byte{}, forged.Data...)
+	forged.Data[0] ^= 0xff
+	_, err = Combine([]*Share{shares[0], shares[1], &forged})
+	wantCode(t, err, ErrorDigestMismatch)
+}
+
+func TestCombineErrors(t *testing.T) {
+	a, err := Split([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Split([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := Split([]byte("secret"), 3, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		shares []*Share
+		code   errcode.Code
+	}{
+		{"empty", nil, ErrorNotEnoughShares},
+		{"mixed splits", []*Share{a[0], b[1]}, ErrorMixedShares},
+		{"mixed thresholds", []*Share{c[0], c[1], a[2]}, ErrorMixedShares},
+		{"duplicate", []*Share{a[0], a[0]}, ErrorDuplicateShare},
+		{"duplicate index", []*Share{a[1], {ID: a[1].ID, Threshold: 2, Index: a[1].Index, Data: a[0].Data}},
+			ErrorDuplicateShare},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Combine(tt.shares)
+			wantCode(t, err, tt.code)
+		})
+	}
+}
+
+func TestSplitErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		secret []byte
+		n, k   int
+		code   errcode.Code
+	}{
+		{"threshold too small", []byte("s"), 3, 1, ErrorInvalidParams},
+		{"threshold above count", []byte("s"), 2, 3, ErrorInvalidParams},
+		{"too many shares", []byte("s"), MaxShares + 1, 2, ErrorInvalidParams},
+		{"empty secret", nil, 3, 2, ErrorInvalidSecret},
+		{"secret too long", make([]byte, MaxSecret+1), 3, 2, ErrorInvalidSecret},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Split(tt.secret, tt.n, tt.k)
+			wantCode(t, err, tt.code)
+		})
+	}
+}
+
+func TestParseShare(t *testing.T) {
+	shares, err := Split([]byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := shares[0].String()
+	parsed, err := ParseShare("  " + strings.ToUpper(text) + "\n")
+	if err != nil {
+		t.Fatalf("ParseShare() error = %v", err)
+	}
+	if parsed.String() != text {
+		t.Errorf("ParseShare().String() = %q, want %q", parsed.String(), text)
+	}
+	parts := strings.Split(text, "-")
+	// 修改数据中的一位,校验和不再匹配
+	data := []byte(parts[4])
+	if data[0] == '0' {
+		data[0] = '1'
+	} else {
+		data[0] = '0'
+	}
+	tampered := strings.Join(append(append([]string{}, parts[:4]...), string(data), parts[5]), "-")
+	tests := []struct {
+		name string
+		text string
+		code errcode.Code
+	}{
+		{"checksum", tampered, ErrorChecksumMismatch},
+		{"prefix", strings.Replace(text, SharePrefix, "pgs0", 1), ErrorInvalidShare},
+		{"parts", strings.Join(parts[:5], "-"), ErrorInvalidShare},
+		{"checksum format", strings.Join(append(append([]string{}, parts[:5]...), "xyz"), "-"), ErrorInvalidShare},
+		{"empty", "", ErrorInvalidShare},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseShare(tt.text)
+			wantCode(t, err, tt.code)
+		})
+	}
+	_, err = ParseShares([]string{text, "", tampered})
+	wantCode(t, err, ErrorChecksumMismatch)
+	if err == nil || !strings.HasPrefix(err.Error(), "share 3:") {
+		t.Errorf("ParseShares() error = %v, want the share number", err)
+	}
+}
+
+func TestParseShareCount(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+		ok   bool
+	}{
+		{"2", 2, true},
+		{" 255 ", 255, true},
+		{"1", 0, false},
+		{"256", 0, false},
+		{"-3", 0, false},
+		{"three", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseShareCount(tt.text)
+		if tt.ok {
+			if err != nil || got != tt.want {
+				t.Errorf("ParseShareCount(%q) = %d, %v, want %d", tt.text, got, err, tt.want)
+			}
+			continue
+		}
+		var codeErr *errcode.Error
+		if !errors.As(err, &codeErr) || codeErr.Code != ErrorInvalidShareCount {
+			t.Errorf("ParseShareCount(%q) error = %v, want %s", tt.text, err, ErrorInvalidShareCount)
+		}
+	}
+}
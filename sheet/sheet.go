@@ -0,0 +1,241 @@
+package sheet
+
+import (
+	"bytes"
+	"fmt"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"passwdgen/errcode"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatText Format = "text"
+	FormatPDF  Format = "pdf"
+	FormatPNG  Format = "png"
+)
+
+// Formats 所有支持的导出格式
+var Formats = []Format{FormatText, FormatPDF, FormatPNG}
+
+const (
+	// ErrorInvalidFormat 不支持的导出格式
+	ErrorInvalidFormat errcode.Code = "invalid_sheet_format"
+	// ErrorUnsupportedChars 条目包含PDF和PNG无法显示的字符
+	ErrorUnsupportedChars errcode.Code = "unsupported_sheet_characters"
+)
+
+// 登记错误码对应的提示消息
+func init() {
+	errcode.Register(ErrorInvalidFormat, "ErrorInvalidSheetFormatMessage")
+	errcode.Register(ErrorUnsupportedChars, "ErrorUnsupportedSheetCharsMessage")
+}
+
+var invalidFormatError = errcode.New(ErrorInvalidFormat)
+var unsupportedCharsError = errcode.New(ErrorUnsupportedChars)
+
+// Sheet 可打印的清单,例如一组恢复码
+type Sheet struct {
+	Title string
+	// 标题下方的说明,例如生成时间
+	Notes []string
+	// 按序号编号的条目
+	Items []string
+}
+
+// ParseFormat 解析导出格式名称
+func ParseFormat(format string) (Format, error) {
+	for _, f := range Formats {
+		if string(f) == strings.ToLower(format) {
+			return f, nil
+		}
+	}
+	return "", invalidFormatError
+}
+
+// Extension 返回导出格式的文件扩展名
+func (f Format) Extension() string {
+	if f == FormatText {
+		return ".txt"
+	}
+	return "." + string(f)
+}
+
+// Write 按指定格式导出
+func (s *Sheet) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatText:
+		return s.WriteText(w)
+	case FormatPDF:
+		return s.WritePDF(w)
+	case FormatPNG:
+		return s.WritePNG(w)
+	}
+	return invalidFormatError
+}
+
+// Check 检查条目能否按指定格式导出,便于在创建输出文件前报错
+func (s *Sheet) Check(format Format) error {
+	switch format {
+	case FormatText:
+		return nil
+	case FormatPDF, FormatPNG:
+		return s.checkItems()
+	}
+	return invalidFormatError
+}
+
+// lines 标题、说明和编号后的条目
+func (s *Sheet) lines() []string {
+	lines := []string{s.Title, ""}
+	if len(s.Notes) > 0 {
+		lines = append(lines, s.Notes...)
+		lines = append(lines, "")
+	}
+	width := len(fmt.Sprint(len(s.Items)))
+	for i, item := range s.Items {
+		lines = append(lines, fmt.Sprintf("%*d. %s", width, i+1, item))
+	}
+	return lines
+}
+
+// WriteText 导出为纯文本
+func (s *Sheet) WriteText(w io.Writer) error {
+	_, err := io.WriteString(w, strings.Join(s.lines(), "\n")+"\n")
+	return err
+}
+
+// checkItems 条目须为可打印ASCII字符,标准字体和点阵字体没有其它字符的字形,替换后打印的条目会与实际不符
+func (s *Sheet) checkItems() error {
+	for i, item := range s.Items {
+		for _, r := range item {
+			if !printableASCII(r) {
+				return fmt.Errorf("item %d: %w", i+1, unsupportedCharsError)
+			}
+		}
+	}
+	return nil
+}
+
+func printableASCII(r rune) bool {
+	return r >= 0x20 && r <= 0x7e
+}
+
+// WritePNG 使用内置点阵字体导出为图片,放大后便于打印。条目包含非ASCII字符时返回错误
+func (s *Sheet) WritePNG(w io.Writer) error {
+	if err := s.checkItems(); err != nil {
+		return err
+	}
+	const (
+		scale  = 3
+		margin = 16
+	)
+	face := basicfont.Face7x13
+	lines := s.lines()
+	maxWidth := 0
+	for _, line := range lines {
+		if width := font.MeasureString(face, line).Ceil(); width > maxWidth {
+			maxWidth = width
+		}
+	}
+	bounds := image.Rect(0, 0, maxWidth+2*margin, len(lines)*face.Height+2*margin)
+	small := image.NewGray(bounds)
+	draw.Draw(small, bounds, image.White, image.Point{}, draw.Src)
+	drawer := &font.Drawer{Dst: small, Src: image.Black, Face: face}
+	for i, line := range lines {
+		drawer.Dot = fixed.P(margin, margin+(i+1)*face.Height-face.Descent)
+		drawer.DrawString(line)
+	}
+	// 最近邻放大,保持点阵字体清晰
+	large := image.NewGray(image.Rect(0, 0, bounds.Dx()*scale, bounds.Dy()*scale))
+	for y := 0; y < large.Bounds().Dy(); y++ {
+		for x := 0; x < large.Bounds().Dx(); x++ {
+			large.SetGray(x, y, color.Gray{Y: small.GrayAt(x/scale, y/scale).Y})
+		}
+	}
+	return png.Encode(w, large)
+}
+
+// WritePDF 导出为A4大小的PDF,使用标准Courier字体,超出一页时自动分页。条目包含非ASCII字符时返回错误
+func (s *Sheet) WritePDF(w io.Writer) error {
+	if err := s.checkItems(); err != nil {
+		return err
+	}
+	const (
+		pageWidth    = 595
+		pageHeight   = 842
+		margin       = 56
+		fontSize     = 12
+		lineHeight   = 18
+		linesPerPage = (pageHeight - 2*margin) / lineHeight
+	)
+	lines := s.lines()
+	var pages [][]string
+	for len(lines) > 0 {
+		n := linesPerPage
+		if n > len(lines) {
+			n = len(lines)
+		}
+		pages = append(pages, lines[:n])
+		lines = lines[n:]
+	}
+	// 对象编号: 1 目录, 2 页面树, 3 字体, 之后每页占用页面和内容两个对象
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	buf.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>")
+	for i, page := range pages {
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT\n/F1 %d Tf\n%d TL\n%d %d Td\n", fontSize, lineHeight, margin, pageHeight-margin)
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) '\n", escapePDF(line))
+		}
+		content.WriteString("ET")
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 5+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// escapePDF 转义PDF字符串,标准字体不支持的字符替换为?,只用于标题和说明
+func escapePDF(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case !printableASCII(r):
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package sheet
+
+import (
+	"bytes"
+	"errors"
+	"passwdgen/errcode"
+	"strings"
+	"testing"
+)
+
+func TestWriteUnsupportedChars(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []string
+		ok    bool
+	}{
+		{"ascii", []string{"ABCD-EFGH", "a1b2-c3d4"}, true},
+		{"chinese", []string{"ABCD-EFGH", "密码-口令"}, false},
+		{"emoji", []string{"🔑🔒-🔓🔐"}, false},
+		{"latin1", []string{"äöüß-éèêë"}, false},
+		{"control", []string{"ABCD\tEFGH"}, false},
+	}
+	for _, tt := range tests {
+		for _, format := range []Format{FormatPDF, FormatPNG} {
+			t.Run(tt.name+"/"+string(format), func(t *testing.T) {
+				s := &Sheet{Title: "Recovery codes", Items: tt.items}
+				var buf bytes.Buffer
+				err := s.Write(&buf, format)
+				if checkErr := s.Check(format); (checkErr == nil) != (err == nil) {
+					t.Errorf("Check() = %v, Write() = %v", checkErr, err)
+				}
+				if tt.ok {
+					if err != nil {
+						t.Fatalf("Write() error = %v", err)
+					}
+					if buf.Len() == 0 {
+						t.Error("Write() wrote nothing")
+					}
+					return
+				}
+				var codeErr *errcode.Error
+				if !errors.As(err, &codeErr) || codeErr.Code != ErrorUnsupportedChars {
+					t.Fatalf("Write() error = %v, want %s", err, ErrorUnsupportedChars)
+				}
+				if buf.Len() != 0 {
+					t.Errorf("Write() wrote %d bytes before failing", buf.Len())
+				}
+			})
+		}
+	}
+}
+
+func TestWriteTextKeepsUnicode(t *testing.T) {
+	s := &Sheet{Title: "恢复码", Items: []string{"密码-口令", "🔑🔒-🔓🔐"}}
+	if err := s.Check(FormatText); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := s.Write(&buf, FormatText); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	for _, item := range s.Items {
+		if !strings.Contains(buf.String(), item) {
+			t.Errorf("text sheet %q does not contain %q", buf.String(), item)
+		}
+	}
+}
+
+func TestWritePDFTitleFallback(t *testing.T) {
+	s := &Sheet{Title: "恢复码", Items: []string{"ABCD-EFGH"}}
+	var buf bytes.Buffer
+	if err := s.Write(&buf, FormatPDF); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) {
+		t.Errorf("output is not a PDF: %q", buf.Bytes()[:8])
+	}
+}
@@ -0,0 +1,55 @@
+package theme
+
+import (
+	"fmt"
+	"fyne.io/fyne/v2"
+	fyneTheme "fyne.io/fyne/v2/theme"
+	"github.com/goki/freetype/truetype"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// MinFontScale 界面字号的最小缩放比例
+	MinFontScale = 0.5
+	// MaxFontScale 界面字号的最大缩放比例
+	MaxFontScale = 3
+)
+
+// monospaceFont 密码使用的等宽字体,fyne内置的 DejaVu Sans Mono,0/O 和 l/I/1 字形可区分
+var monospaceFont = fyneTheme.DefaultTextMonospaceFont()
+
+// textSizeNames 随字号缩放比例调整的文字大小
+var textSizeNames = map[fyne.ThemeSizeName]bool{
+	fyneTheme.SizeNameText:           true,
+	fyneTheme.SizeNameHeadingText:    true,
+	fyneTheme.SizeNameSubHeadingText: true,
+	fyneTheme.SizeNameCaptionText:    true,
+}
+
+// InvalidFontError 字体文件无法读取或解析
+type InvalidFontError struct {
+	Path   string
+	Reason string
+}
+
+func (e *InvalidFontError) Error() string {
+	return fmt.Sprintf("font %s: %s", e.Path, e.Reason)
+}
+
+// LoadFont 读取并校验 TrueType 字体文件,用作界面字体
+func LoadFont(path string) (fyne.Resource, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &InvalidFontError{Path: path, Reason: err.Error()}
+	}
+	return ParseFont(filepath.Base(path), content)
+}
+
+// ParseFont 校验字体内容,界面渲染只支持 TrueType 字体
+func ParseFont(name string, content []byte) (fyne.Resource, error) {
+	if _, err := truetype.Parse(content); err != nil {
+		return nil, &InvalidFontError{Path: name, Reason: err.Error()}
+	}
+	return fyne.NewStaticResource(name, content), nil
+}
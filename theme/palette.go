@@ -0,0 +1,549 @@
+package theme
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"fyne.io/fyne/v2"
+	fyneTheme "fyne.io/fyne/v2/theme"
+	"github.com/BurntSushi/toml"
+	"image/color"
+	"io"
+	"os"
+	"passwdgen/errcode"
+	"passwdgen/gen"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// 内置主题的名称,保存在设置中,界面显示按语言翻译的名称
+	DarkTheme  = "Dark"
+	LightTheme = "Light"
+	// SystemTheme 跟随系统的亮色或暗色设置
+	SystemTheme = "System"
+
+	AppDirName = "passwdgen"
+	// DirName 用户主题目录,位于配置目录下,每个 <编号>.toml 文件为一个主题
+	DirName       = "themes"
+	FileExtension = ".toml"
+)
+
+// Source 主题来源
+type Source string
+
+const (
+	SourceBuiltin  Source = "builtin"
+	SourceEmbedded Source = "embedded"
+	SourceUser     Source = "user"
+)
+
+//go:embed themes/*.toml
+var embeddedFS embed.FS
+
+// ColorNames 主题文件中可以定义的颜色
+var ColorNames = []fyne.ThemeColorName{
+	fyneTheme.ColorNameBackground,
+	fyneTheme.ColorNameButton,
+	fyneTheme.ColorNameDisabledButton,
+	fyneTheme.ColorNameDisabled,
+	fyneTheme.ColorNameError,
+	fyneTheme.ColorNameFocus,
+	fyneTheme.ColorNameForeground,
+	fyneTheme.ColorNameHover,
+	fyneTheme.ColorNameInputBackground,
+	fyneTheme.ColorNameInputBorder,
+	fyneTheme.ColorNameMenuBackground,
+	fyneTheme.ColorNameOverlayBackground,
+	fyneTheme.ColorNamePlaceHolder,
+	fyneTheme.ColorNamePressed,
+	fyneTheme.ColorNamePrimary,
+	fyneTheme.ColorNameScrollBar,
+	fyneTheme.ColorNameSelection,
+	fyneTheme.ColorNameSeparator,
+	fyneTheme.ColorNameShadow,
+	fyneTheme.ColorNameSuccess,
+	fyneTheme.ColorNameWarning,
+}
+
+// strengthKeys 主题文件 [strength] 中强度等级的名称
+var strengthKeys = []struct {
+	key      string
+	strength gen.Strength
+}{
+	{"very_weak", gen.StrengthVeryWeak},
+	{"weak", gen.StrengthWeak},
+	{"normal", gen.StrengthNormal},
+	{"strong", gen.StrengthStrong},
+	{"very_strong", gen.StrengthVeryStrong},
+}
+
+// costKey 主题文件 [strength] 中破解时间颜色的名称
+const costKey = "cost"
+
+// lightStrengthColors 亮色背景下的强度等级颜色,默认的黄色和绿色在白色背景上难以辨认
+var lightStrengthColors = map[gen.Strength]color.Color{
+	gen.StrengthVeryWeak:   color.NRGBA{R: 0xd3, G: 0x2f, B: 0x2f, A: 0xff},
+	gen.StrengthWeak:       color.NRGBA{R: 0xe6, G: 0x51, B: 0x00, A: 0xff},
+	gen.StrengthNormal:     color.NRGBA{R: 0xa0, G: 0x7a, B: 0x00, A: 0xff},
+	gen.StrengthStrong:     color.NRGBA{R: 0x1a, G: 0x53, B: 0xc9, A: 0xff},
+	gen.StrengthVeryStrong: color.NRGBA{R: 0x38, G: 0x8e, B: 0x3c, A: 0xff},
+}
+
+// lightCostColor 亮色背景下破解时间的颜色
+var lightCostColor = color.NRGBA{R: 0xe6, G: 0x51, B: 0x00, A: 0xff}
+
+var idRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+var invalidIdChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// ErrorThemeNotFound 主题不存在
+const ErrorThemeNotFound errcode.Code = "theme_not_found"
+
+// 登记错误码对应的提示消息
+func init() {
+	errcode.Register(ErrorThemeNotFound, "ErrorThemeNotFoundMessage")
+}
+
+var ThemeNotFoundError = errcode.New(ErrorThemeNotFound)
+
+// legacyNames 旧版本保存在设置中的内置主题名称
+var legacyNames = map[string]string{
+	"Dark(暗黑)":     DarkTheme,
+	"Light(白色)":    LightTheme,
+	"System(跟随系统)": SystemTheme,
+}
+
+// CanonicalName 返回旧版本内置主题名称对应的当前名称,其余名称原样返回
+func CanonicalName(name string) string {
+	if canonical, ok := legacyNames[name]; ok {
+		return canonical
+	}
+	return name
+}
+
+// Palette 主题配色,未定义的颜色使用基础亮色或暗色主题的颜色
+type Palette struct {
+	// 文件名(去掉扩展名)
+	Id string
+	// 显示名称,也是设置中保存的主题名称
+	Name   string
+	Source Source
+	// 用户主题的文件路径
+	Path    string
+	Variant fyne.ThemeVariant
+	// 是否使用系统的亮色或暗色设置,为true时忽略 Variant
+	FollowSystem bool
+	Colors       map[fyne.ThemeColorName]color.Color
+	// 强度等级的颜色,未定义时使用默认颜色
+	Strength map[gen.Strength]color.Color
+	Cost     color.Color
+}
+
+// ResolveVariant 返回实际使用的亮色或暗色,systemVariant 为系统当前的设置
+func (p *Palette) ResolveVariant(systemVariant fyne.ThemeVariant) fyne.ThemeVariant {
+	if p.FollowSystem {
+		return systemVariant
+	}
+	return p.Variant
+}
+
+// Color 返回主题颜色,systemVariant 为系统当前的亮色或暗色设置
+func (p *Palette) Color(colorName fyne.ThemeColorName, systemVariant fyne.ThemeVariant) color.Color {
+	if c, ok := p.Colors[colorName]; ok {
+		return c
+	}
+	return fyneTheme.DefaultTheme().Color(colorName, p.ResolveVariant(systemVariant))
+}
+
+// StrengthColor 返回强度等级的颜色,主题未定义时按亮色或暗色背景选择默认颜色,未知等级返回nil
+func (p *Palette) StrengthColor(strength gen.Strength, systemVariant fyne.ThemeVariant) color.Color {
+	if strength == gen.StrengthUnknown {
+		return nil
+	}
+	if c, ok := p.Strength[strength]; ok {
+		return c
+	}
+	if p.ResolveVariant(systemVariant) == fyneTheme.VariantLight {
+		return lightStrengthColors[strength]
+	}
+	return strength.Color()
+}
+
+// CostColor 返回破解时间的颜色,未知等级返回nil
+func (p *Palette) CostColor(strength gen.Strength, systemVariant fyne.ThemeVariant) color.Color {
+	if strength == gen.StrengthUnknown {
+		return nil
+	}
+	if p.Cost != nil {
+		return p.Cost
+	}
+	if p.ResolveVariant(systemVariant) == fyneTheme.VariantLight {
+		return lightCostColor
+	}
+	return strength.CostColor()
+}
+
+// InvalidThemeError 主题文件校验错误
+type InvalidThemeError struct {
+	Id     string
+	Path   string
+	Reason string
+}
+
+func (e *InvalidThemeError) Error() string {
+	location := e.Id
+	if e.Path != "" {
+		location = e.Path
+	}
+	return fmt.Sprintf("theme %s: %s", location, e.Reason)
+}
+
+// LoadError 加载用户主题时的所有错误,出错的主题不会被注册
+type LoadError struct {
+	Errors []error
+}
+
+func (le *LoadError) Error() string {
+	lines := make([]string, 0, len(le.Errors)+1)
+	lines = append(lines, fmt.Sprintf("invalid themes (%d problem(s)):", len(le.Errors)))
+	for _, err := range le.Errors {
+		lines = append(lines, "  "+err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// paletteFile 主题文件格式
+type paletteFile struct {
+	Name     string            `toml:"name"`
+	Variant  string            `toml:"variant"`
+	Colors   map[string]string `toml:"colors"`
+	Strength map[string]string `toml:"strength"`
+}
+
+// Parse 读取并校验主题文件,未知的颜色名称或无法解析的颜色会导致整个主题被拒绝
+func Parse(id string, r io.Reader) (*Palette, error) {
+	if !idRegexp.MatchString(id) {
+		return nil, &InvalidThemeError{Id: id, Reason: "file name must consist of lowercase letters, digits, '-' or '_'"}
+	}
+	var pf paletteFile
+	md, err := toml.NewDecoder(r).Decode(&pf)
+	if err != nil {
+		return nil, &InvalidThemeError{Id: id, Reason: err.Error()}
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return nil, &InvalidThemeError{Id: id, Reason: fmt.Sprintf("unknown key %q", undecoded[0].String())}
+	}
+	p := &Palette{
+		Id:       id,
+		Name:     strings.TrimSpace(pf.Name),
+		Colors:   make(map[fyne.ThemeColorName]color.Color),
+		Strength: make(map[gen.Strength]color.Color),
+	}
+	if p.Name == "" {
+		p.Name = id
+	}
+	switch pf.Variant {
+	case "", "dark":
+		p.Variant = fyneTheme.VariantDark
+	case "light":
+		p.Variant = fyneTheme.VariantLight
+	default:
+		return nil, &InvalidThemeError{Id: id, Reason: fmt.Sprintf("invalid variant %q: must be dark or light", pf.Variant)}
+	}
+	known := make(map[string]bool, len(ColorNames))
+	for _, colorName := range ColorNames {
+		known[string(colorName)] = true
+	}
+	for _, key := range sortedKeys(pf.Colors) {
+		value := pf.Colors[key]
+		if !known[key] {
+			return nil, &InvalidThemeError{Id: id, Reason: fmt.Sprintf("unknown color %q", key)}
+		}
+		c, err := ParseColor(value)
+		if err != nil {
+			return nil, &InvalidThemeError{Id: id, Reason: fmt.Sprintf("color %q: %s", key, err)}
+		}
+		p.Colors[fyne.ThemeColorName(key)] = c
+	}
+	for _, key := range sortedKeys(pf.Strength) {
+		value := pf.Strength[key]
+		c, err := ParseColor(value)
+		if err != nil {
+			return nil, &InvalidThemeError{Id: id, Reason: fmt.Sprintf("strength color %q: %s", key, err)}
+		}
+		if key == costKey {
+			p.Cost = c
+			continue
+		}
+		strength, ok := strengthOf(key)
+		if !ok {
+			return nil, &InvalidThemeError{Id: id, Reason: fmt.Sprintf("unknown strength %q", key)}
+		}
+		p.Strength[strength] = c
+	}
+	return p, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func strengthOf(key string) (gen.Strength, bool) {
+	for _, sk := range strengthKeys {
+		if sk.key == key {
+			return sk.strength, true
+		}
+	}
+	return gen.StrengthUnknown, false
+}
+
+// ParseColor 解析 #rgb、#rrggbb 或 #rrggbbaa 格式的颜色
+func ParseColor(value string) (color.Color, error) {
+	invalid := fmt.Errorf("invalid color %q: must be #rgb, #rrggbb or #rrggbbaa", value)
+	hex := strings.TrimSpace(value)
+	if !strings.HasPrefix(hex, "#") {
+		return nil, invalid
+	}
+	hex = hex[1:]
+	switch len(hex) {
+	case 3:
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]}) + "ff"
+	case 6:
+		hex += "ff"
+	case 8:
+	default:
+		return nil, invalid
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, invalid
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// FormatColor 把颜色格式化为 #rrggbb,不透明度不是100%时为 #rrggbbaa
+func FormatColor(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if n.A == 0xff {
+		return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", n.R, n.G, n.B, n.A)
+}
+
+// Encode 把主题的所有颜色(包括基础主题的颜色)写为主题文件,可以作为自定义主题的模板。
+// 跟随系统的主题按 systemVariant 导出
+func Encode(w io.Writer, p *Palette, systemVariant fyne.ThemeVariant) error {
+	var buf bytes.Buffer
+	variant := "dark"
+	if p.ResolveVariant(systemVariant) == fyneTheme.VariantLight {
+		variant = "light"
+	}
+	fmt.Fprintf(&buf, "name = %q\n", p.Name)
+	fmt.Fprintf(&buf, "# base theme for colors that are not listed: dark or light\n")
+	fmt.Fprintf(&buf, "variant = %q\n\n", variant)
+	fmt.Fprintf(&buf, "[colors]\n")
+	for _, colorName := range ColorNames {
+		fmt.Fprintf(&buf, "%s = %q\n", colorName, FormatColor(p.Color(colorName, systemVariant)))
+	}
+	fmt.Fprintf(&buf, "\n[strength]\n")
+	for _, sk := range strengthKeys {
+		fmt.Fprintf(&buf, "%s = %q\n", sk.key, FormatColor(p.StrengthColor(sk.strength, systemVariant)))
+	}
+	fmt.Fprintf(&buf, "%s = %q\n", costKey, FormatColor(p.CostColor(gen.StrengthVeryWeak, systemVariant)))
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Registry 主题注册表,包含内置主题和用户目录中的主题
+type Registry struct {
+	mu       sync.RWMutex
+	palettes map[string]*Palette
+	names    []string
+	// 用户主题的加载错误
+	err error
+}
+
+// NewRegistry 返回只包含内置主题的注册表
+func NewRegistry() *Registry {
+	reg := &Registry{palettes: make(map[string]*Palette)}
+	reg.add(&Palette{Id: "dark", Name: DarkTheme, Source: SourceBuiltin, Variant: fyneTheme.VariantDark})
+	reg.add(&Palette{Id: "light", Name: LightTheme, Source: SourceBuiltin, Variant: fyneTheme.VariantLight})
+	reg.add(&Palette{Id: "system", Name: SystemTheme, Source: SourceBuiltin, Variant: fyneTheme.VariantDark, FollowSystem: true})
+	paths, err := embeddedFS.ReadDir("themes")
+	if err != nil {
+		panic(err)
+	}
+	for _, entry := range paths {
+		f, err := embeddedFS.Open("themes/" + entry.Name())
+		if err != nil {
+			panic(err)
+		}
+		p, err := Parse(strings.TrimSuffix(entry.Name(), FileExtension), f)
+		_ = f.Close()
+		if err != nil {
+			panic(err)
+		}
+		p.Source = SourceEmbedded
+		reg.add(p)
+	}
+	return reg
+}
+
+// DefaultDir 返回用户主题目录
+func DefaultDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, AppDirName, DirName), nil
+}
+
+// LoadDir 加载目录中的 *.toml 主题,目录不存在时忽略。
+// 校验失败或与已有主题重名的主题不会被注册,所有错误合并为 *LoadError 返回
+func (reg *Registry) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+FileExtension))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+	var errs []error
+	for _, path := range paths {
+		p, err := ParseFile(path)
+		if err == nil {
+			err = reg.checkConflict(p)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		reg.add(p)
+	}
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.err = nil
+	if len(errs) > 0 {
+		reg.err = &LoadError{Errors: errs}
+	}
+	return reg.err
+}
+
+// ParseFile 读取并校验用户主题文件,文件名(去掉扩展名)即主题编号
+func ParseFile(path string) (*Palette, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p, err := Parse(strings.TrimSuffix(filepath.Base(path), FileExtension), f)
+	if err != nil {
+		var ite *InvalidThemeError
+		if errors.As(err, &ite) {
+			ite.Path = path
+		}
+		return nil, err
+	}
+	p.Source = SourceUser
+	p.Path = path
+	return p, nil
+}
+
+// Import 校验主题文件并复制到用户主题目录后注册
+func (reg *Registry) Import(name string, r io.Reader, dir string) (*Palette, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	// 文件名中不能作为主题编号的字符替换为 -
+	id := strings.Trim(invalidIdChars.ReplaceAllString(
+		strings.ToLower(strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))), "-"), "-_")
+	p, err := Parse(id, bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if err := reg.checkConflict(p); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, id+FileExtension)
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		return nil, err
+	}
+	p.Source = SourceUser
+	p.Path = path
+	reg.add(p)
+	return p, nil
+}
+
+// checkConflict 主题编号和显示名称都不能与已注册的主题重复
+func (reg *Registry) checkConflict(p *Palette) error {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	for _, registered := range reg.palettes {
+		if registered.Id == p.Id || registered.Name == p.Name {
+			return &InvalidThemeError{Id: p.Id, Path: p.Path,
+				Reason: fmt.Sprintf("theme %q is already registered", registered.Name)}
+		}
+	}
+	return nil
+}
+
+func (reg *Registry) add(p *Palette) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.palettes[p.Name] = p
+	reg.names = append(reg.names, p.Name)
+}
+
+// Get 按显示名称返回主题
+func (reg *Registry) Get(name string) (*Palette, error) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	p, ok := reg.palettes[name]
+	if !ok {
+		return nil, ThemeNotFoundError
+	}
+	return p, nil
+}
+
+// Names 返回所有主题的显示名称,内置主题在前
+func (reg *Registry) Names() []string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	names := make([]string, len(reg.names))
+	copy(names, reg.names)
+	return names
+}
+
+// Err 返回用户主题的加载错误
+func (reg *Registry) Err() error {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	return reg.err
+}
+
+var defaultRegistry *Registry
+var defaultOnce sync.Once
+
+// Default 返回内置主题和默认用户目录主题组成的注册表,用户主题的错误通过 Err 获取
+func Default() *Registry {
+	defaultOnce.Do(func() {
+		defaultRegistry = NewRegistry()
+		if dir, err := DefaultDir(); err == nil {
+			_ = defaultRegistry.LoadDir(dir)
+		}
+	})
+	return defaultRegistry
+}
@@ -4,6 +4,7 @@ import (
 	"fyne.io/fyne/v2"
 	fyneTheme "fyne.io/fyne/v2/theme"
 	"image/color"
+	"passwdgen/gen"
 	"strings"
 )
 import _ "embed"
@@ -26,11 +27,29 @@ var Logo = &fyne.StaticResource{
 
 type SelectableTheme struct {
 	Theme string
+	// 主题配色,nil时按名称选择默认的亮色或暗色
+	Palette *Palette
+	// 用户选择的界面字体,nil时使用内置字体;等宽文字始终使用内置等宽字体
+	UserFont fyne.Resource
+	// 界面字号缩放比例,0表示不缩放
+	FontScale float32
 }
 
 var _ fyne.Theme = (*SelectableTheme)(nil)
 
+// NewSelectableTheme 按显示名称返回注册表中的主题,主题不存在时按名称选择亮色或暗色
+func NewSelectableTheme(name string) *SelectableTheme {
+	st := &SelectableTheme{Theme: name}
+	if p, err := Default().Get(name); err == nil {
+		st.Palette = p
+	}
+	return st
+}
+
 func (st SelectableTheme) Color(colorName fyne.ThemeColorName, variant fyne.ThemeVariant) color.Color {
+	if st.Palette != nil {
+		return st.Palette.Color(colorName, variant)
+	}
 	if strings.Contains(st.Theme, "Light") {
 		variant = fyneTheme.VariantLight
 	} else {
@@ -39,14 +58,40 @@ func (st SelectableTheme) Color(colorName fyne.ThemeColorName, variant fyne.Them
 	return fyneTheme.DefaultTheme().Color(colorName, variant)
 }
 
+// StrengthColor 返回主题中强度等级的颜色,variant 为系统当前的亮色或暗色设置,不是 SelectableTheme 时使用默认颜色
+func StrengthColor(t fyne.Theme, variant fyne.ThemeVariant, strength gen.Strength) color.Color {
+	if st, ok := t.(*SelectableTheme); ok && st.Palette != nil {
+		return st.Palette.StrengthColor(strength, variant)
+	}
+	return strength.Color()
+}
+
+// CostColor 返回主题中破解时间的颜色,variant 为系统当前的亮色或暗色设置,不是 SelectableTheme 时使用默认颜色
+func CostColor(t fyne.Theme, variant fyne.ThemeVariant, strength gen.Strength) color.Color {
+	if st, ok := t.(*SelectableTheme); ok && st.Palette != nil {
+		return st.Palette.CostColor(strength, variant)
+	}
+	return strength.CostColor()
+}
+
 func (st SelectableTheme) Icon(iconName fyne.ThemeIconName) fyne.Resource {
 	return fyneTheme.DefaultTheme().Icon(iconName)
 }
 
-func (st SelectableTheme) Font(_ fyne.TextStyle) fyne.Resource {
+func (st SelectableTheme) Font(style fyne.TextStyle) fyne.Resource {
+	if style.Monospace {
+		return monospaceFont
+	}
+	if st.UserFont != nil {
+		return st.UserFont
+	}
 	return font
 }
 
 func (st SelectableTheme) Size(sizeName fyne.ThemeSizeName) float32 {
-	return fyneTheme.DefaultTheme().Size(sizeName)
+	size := fyneTheme.DefaultTheme().Size(sizeName)
+	if textSizeNames[sizeName] && st.FontScale >= MinFontScale && st.FontScale <= MaxFontScale {
+		size *= st.FontScale
+	}
+	return size
 }
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
+	"passwdgen/gen"
+	"passwdgen/i18n"
+	"strings"
+)
+
+// charClassLabelKeys 内置字符类的显示名称
+var charClassLabelKeys = map[string]i18n.MessageId{
+	gen.ClassNameLatin1:   i18n.CharClassLatin1CheckLabelKey,
+	gen.ClassNameCyrillic: i18n.CharClassCyrillicCheckLabelKey,
+	gen.ClassNameGreek:    i18n.CharClassGreekCheckLabelKey,
+	gen.ClassNameHex:      i18n.CharClassHexCheckLabelKey,
+	gen.ClassNameBase58:   i18n.CharClassBase58CheckLabelKey,
+	gen.ClassNameEmoji:    i18n.CharClassEmojiCheckLabelKey,
+}
+
+// newCharClassChecks 按 gen.NamedCharClasses 的顺序为每个内置字符类创建开关,选中项以逗号分隔写入classes
+func newCharClassChecks(texts textRegistrar, classes binding.String, onChanged func()) []*widget.Check {
+	checks := make([]*widget.Check, 0, len(gen.NamedCharClasses))
+	update := func(bool) {
+		var selected []string
+		for i, check := range checks {
+			if check.Checked {
+				selected = append(selected, gen.NamedCharClasses[i])
+			}
+		}
+		_ = classes.Set(strings.Join(selected, ","))
+		onChanged()
+	}
+	for _, name := range gen.NamedCharClasses {
+		checks = append(checks, newCheckWidget(texts, name, charClassLabelKeys[name], update, false))
+	}
+	refreshCharClassChecks(checks, classes)
+	return checks
+}
+
+// refreshCharClassChecks 按classes更新开关状态
+func refreshCharClassChecks(checks []*widget.Check, classes binding.String) {
+	enabled, _ := gen.ParseCharClasses(getStringBindingValue(classes))
+	for i, name := range gen.NamedCharClasses {
+		checked := false
+		for _, c := range enabled {
+			checked = checked || c == name
+		}
+		checks[i].Checked = checked
+		checks[i].Refresh()
+	}
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"passwdgen/gen"
+	"unicode/utf8"
+)
+
+// ChunkSizes 可选的分组大小,0表示不分组
+var ChunkSizes = []string{"0", "3", "4", "5", "6", "8"}
+
+// classColorNames 字符类别对应的主题颜色,随主题切换
+var classColorNames = map[gen.CharClass]fyne.ThemeColorName{
+	gen.CharClassDigit:     theme.ColorNamePrimary,
+	gen.CharClassLowercase: theme.ColorNameForeground,
+	gen.CharClassUppercase: theme.ColorNameSuccess,
+	gen.CharClassSymbol:    theme.ColorNameWarning,
+}
+
+func newChunkedText() *widget.RichText {
+	rt := widget.NewRichText()
+	rt.Wrapping = fyne.TextWrapWord
+	return rt
+}
+
+// renderChunked 分组显示密码,按字符类别着色;掩码时只显示分组后的掩码字符
+func renderChunked(rt *widget.RichText, passwd string, size int, masked bool) {
+	var segments []widget.RichTextSegment
+	for i, chunk := range gen.Chunk(passwd, size) {
+		if i > 0 {
+			segments = append(segments, newChunkSegment(gen.DefaultChunkSeparator, theme.ColorNameForeground))
+		}
+		if masked {
+			segments = append(segments, newChunkSegment(maskPassword(chunk), theme.ColorNameForeground))
+			continue
+		}
+		// 相邻的同类字符合并为一段
+		start := 0
+		for start < len(chunk) {
+			r, n := utf8.DecodeRuneInString(chunk[start:])
+			class := gen.ClassOf(r)
+			end := start + n
+			for end < len(chunk) {
+				next, m := utf8.DecodeRuneInString(chunk[end:])
+				if gen.ClassOf(next) != class {
+					break
+				}
+				end += m
+			}
+			segments = append(segments, newChunkSegment(chunk[start:end], classColorNames[class]))
+			start = end
+		}
+	}
+	rt.Segments = segments
+	rt.Refresh()
+}
+
+func newChunkSegment(text string, colorName fyne.ThemeColorName) *widget.TextSegment {
+	return &widget.TextSegment{
+		Text: text,
+		Style: widget.RichTextStyle{
+			ColorName: colorName,
+			Inline:    true,
+			SizeName:  theme.SizeNameSubHeadingText,
+			TextStyle: fyne.TextStyle{Monospace: true},
+		},
+	}
+}
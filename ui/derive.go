@@ -0,0 +1,221 @@
+package ui
+
+import (
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"passwdgen/gen"
+	"passwdgen/i18n"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// initDeriveTabContent 由主密码确定性派生站点密码,主密码只保存在输入框中,不会持久化
+func initDeriveTabContent(w fyne.Window, localizer *i18n.Localizer, settings *settings) fyne.CanvasObject {
+	masterEntry := widget.NewPasswordEntry()
+	siteEntry := widget.NewEntry()
+	siteEntry.SetPlaceHolder("example.com")
+	loginEntry := widget.NewEntry()
+	counterEntry := widget.NewEntry()
+	counterEntry.SetText("1")
+	counterEntry.Validator = func(value string) error {
+		_, err := strconv.ParseUint(value, 10, 32)
+		return err
+	}
+	outputEntry := widget.NewPasswordEntry()
+	outputEntry.Password = getBoolBindingValue(settings.alwaysMask)
+	strength := newStrengthView(localizer)
+	progress := widget.NewProgressBarInfinite()
+	progress.Stop()
+	progress.Hide()
+	var mu sync.Mutex
+	// 每次派生的序号,输入变动后丢弃过期的结果
+	var seq int
+	clearOutput := func() {
+		mu.Lock()
+		seq++
+		mu.Unlock()
+		outputEntry.SetText("")
+		strength.set(nil)
+	}
+	for _, entry := range []*widget.Entry{masterEntry, siteEntry, loginEntry, counterEntry} {
+		entry.OnChanged = func(string) {
+			clearOutput()
+		}
+	}
+	// 派生使用独立保存的长度和字符类配置,修改密码生成页不会改变派生结果
+	dcf := newDeriveConfForm(localizer, loadDerivePasswdGenConf(), func(conf *gen.PasswdGenConf) {
+		savePasswdGenConf(prefDeriveConfPrefix, conf)
+		clearOutput()
+	})
+	deriveButton := newOptionButtonWidget(localizer, "", i18n.DeriveButtonLabelKey, theme.ConfirmIcon(), nil)
+	deriveButton.OnTapped = func() {
+		counter, err := strconv.ParseUint(counterEntry.Text, 10, 32)
+		if err != nil {
+			showError(err, w, localizer)
+			return
+		}
+		dc := &gen.DeriveConf{
+			Master:  masterEntry.Text,
+			Site:    strings.TrimSpace(siteEntry.Text),
+			Login:   loginEntry.Text,
+			Counter: uint32(counter),
+			Conf:    dcf.conf(),
+		}
+		clearOutput()
+		mu.Lock()
+		current := seq
+		mu.Unlock()
+		deriveButton.Disable()
+		progress.Show()
+		progress.Start()
+		go func() {
+			result, err := gen.DerivePassword(dc)
+			mu.Lock()
+			stale := current != seq
+			mu.Unlock()
+			progress.Stop()
+			progress.Hide()
+			deriveButton.Enable()
+			if stale {
+				return
+			}
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			outputEntry.SetText(result.Password)
+			strength.set(result)
+		}()
+	}
+	copyButton := newOptionButtonWidget(localizer, "", i18n.CopyButtonLabelKey, theme.ContentCopyIcon(), func() {
+		if outputEntry.Text != "" {
+			copyToClipboard(w, outputEntry.Text)
+		}
+	})
+	peekButton := newOptionButtonWidget(localizer, "", i18n.PeekButtonLabelKey, theme.VisibilityIcon(), func() {
+		peekEntry(outputEntry, settings.alwaysMask)
+	})
+	// 恢复出厂设置时清空输入
+	settings.addFactoryResetListener(func() {
+		masterEntry.SetText("")
+		siteEntry.SetText("")
+		loginEntry.SetText("")
+		counterEntry.SetText("1")
+		dcf.set(newConfiguredPasswdGenConf())
+	})
+	form := container.New(layout.NewFormLayout(),
+		newLabelWidget(localizer, "", i18n.DeriveMasterFormLabelKey), masterEntry,
+		newLabelWidget(localizer, "", i18n.DeriveSiteFormLabelKey), siteEntry,
+		newLabelWidget(localizer, "", i18n.DeriveLoginFormLabelKey), loginEntry,
+		newLabelWidget(localizer, "", i18n.DeriveCounterFormLabelKey), counterEntry,
+		newLabelWidget(localizer, "", i18n.PasswdLengthSlideLabelKey), container.NewBorder(nil, nil, nil, dcf.lengthInfo, dcf.lengthSlide),
+		newLabelWidget(localizer, "", i18n.IncludeSpecialCharSetFormLabelKey), widget.NewEntryWithData(dcf.includeSpecialCharSet),
+		newLabelWidget(localizer, "", i18n.ExcludeSpecialCharSetFormLabelKey), widget.NewEntryWithData(dcf.excludeSpecialCharSet),
+	)
+	deriveBox := container.NewVBox(
+		form,
+		dcf.checkGroup,
+		dcf.classCheckGroup,
+		container.New(layout.NewGridLayout(3), deriveButton, copyButton, peekButton),
+		progress,
+		outputEntry,
+		container.NewHBox(newLabelWidget(localizer, "", i18n.PasswdStrengthLabelKey), strength.info, strength.cost),
+	)
+	deriveCard := widget.NewCard("", "", deriveBox)
+	localizer.Register(i18n.DeriveCardTitleKey, func(value string) {
+		deriveCard.Title = value
+	})
+	return container.NewBorder(deriveCard, nil, nil, nil)
+}
+
+// deriveConfForm 派生密码的长度和字符类配置
+type deriveConfForm struct {
+	lengthInfo            *widget.Label
+	lengthSlide           *widget.Slider
+	numberCheck           *widget.Check
+	lowercaseCheck        *widget.Check
+	uppercaseCheck        *widget.Check
+	duplicateCheck        *widget.Check
+	includeSpecialCharSet binding.String
+	excludeSpecialCharSet binding.String
+	classes               binding.String
+	classChecks           []*widget.Check
+	checkGroup            fyne.CanvasObject
+	classCheckGroup       fyne.CanvasObject
+}
+
+// newDeriveConfForm 按conf创建配置控件,配置变动时调用onChanged
+func newDeriveConfForm(texts textRegistrar, conf *gen.PasswdGenConf, onChanged func(conf *gen.PasswdGenConf)) *deriveConfForm {
+	dcf := &deriveConfForm{
+		includeSpecialCharSet: binding.NewString(),
+		excludeSpecialCharSet: binding.NewString(),
+		classes:               binding.NewString(),
+	}
+	changed := func() {
+		onChanged(dcf.conf())
+	}
+	dcf.lengthInfo = widget.NewLabel("")
+	// 保存的长度超过滑块范围时扩大范围,避免派生结果改变
+	maxLength := 64
+	if int(conf.Length) > maxLength {
+		maxLength = int(conf.Length)
+	}
+	dcf.lengthSlide = widget.NewSlider(1, float64(maxLength))
+	dcf.lengthSlide.Step = 1
+	dcf.lengthSlide.OnChanged = func(value float64) {
+		dcf.lengthInfo.SetText(fmt.Sprintf("%0.0f", value))
+		changed()
+	}
+	dcf.numberCheck = newCheckWidget(texts, "", i18n.NumberCheckLabelKey, func(bool) { changed() }, false)
+	dcf.lowercaseCheck = newCheckWidget(texts, "", i18n.LowercaseCheckLabelKey, func(bool) { changed() }, false)
+	dcf.uppercaseCheck = newCheckWidget(texts, "", i18n.UppercaseCheckLabelKey, func(bool) { changed() }, false)
+	dcf.duplicateCheck = newCheckWidget(texts, "", i18n.DuplicateCheckLabelKey, func(bool) { changed() }, false)
+	dcf.checkGroup = container.New(layout.NewGridLayout(4), dcf.numberCheck, dcf.lowercaseCheck, dcf.uppercaseCheck,
+		dcf.duplicateCheck)
+	dcf.classChecks = newCharClassChecks(texts, dcf.classes, changed)
+	classCheckGroup := container.New(layout.NewGridLayout(len(dcf.classChecks)))
+	for _, check := range dcf.classChecks {
+		classCheckGroup.Add(check)
+	}
+	dcf.classCheckGroup = classCheckGroup
+	dcf.set(conf)
+	listener := binding.NewDataListener(changed)
+	dcf.includeSpecialCharSet.AddListener(listener)
+	dcf.excludeSpecialCharSet.AddListener(listener)
+	return dcf
+}
+
+// set 按conf更新控件
+func (dcf *deriveConfForm) set(conf *gen.PasswdGenConf) {
+	dcf.lengthSlide.SetValue(float64(conf.Length))
+	dcf.lengthInfo.SetText(fmt.Sprint(conf.Length))
+	dcf.numberCheck.SetChecked(conf.EnableNumber)
+	dcf.lowercaseCheck.SetChecked(conf.EnableLowercase)
+	dcf.uppercaseCheck.SetChecked(conf.EnableUppercase)
+	dcf.duplicateCheck.SetChecked(conf.EnableDuplicate)
+	_ = dcf.includeSpecialCharSet.Set(conf.IncludeSpecialCharSet)
+	_ = dcf.excludeSpecialCharSet.Set(conf.ExcludeSpecialCharSet)
+	_ = dcf.classes.Set(strings.Join(conf.Classes, ","))
+	refreshCharClassChecks(dcf.classChecks, dcf.classes)
+}
+
+// conf 返回控件中的配置
+func (dcf *deriveConfForm) conf() *gen.PasswdGenConf {
+	classes, _ := gen.ParseCharClasses(getStringBindingValue(dcf.classes))
+	return &gen.PasswdGenConf{
+		Length:                uint8(dcf.lengthSlide.Value),
+		EnableNumber:          dcf.numberCheck.Checked,
+		EnableLowercase:       dcf.lowercaseCheck.Checked,
+		EnableUppercase:       dcf.uppercaseCheck.Checked,
+		EnableDuplicate:       dcf.duplicateCheck.Checked,
+		IncludeSpecialCharSet: getStringBindingValue(dcf.includeSpecialCharSet),
+		ExcludeSpecialCharSet: getStringBindingValue(dcf.excludeSpecialCharSet),
+		Classes:               classes,
+	}
+}
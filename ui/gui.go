@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -10,9 +11,12 @@ import (
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+	"github.com/rivo/uniseg"
 	"image/color"
 	"passwdgen/gen"
+	"passwdgen/history"
 	"passwdgen/i18n"
+	"passwdgen/profile"
 	pm "passwdgen/theme"
 	"strconv"
 	"strings"
@@ -20,35 +24,67 @@ import (
 )
 
 const (
-	DefaultTheme    = "Dark(暗黑)"
-	DefaultLanguage = "zh_CN(中文)"
+	DefaultTheme    = pm.DarkTheme
+	DefaultLanguage = "zh_CN"
+	// historyTimeLayout 历史记录生成时间的显示格式
+	historyTimeLayout = "2006-01-02 15:04:05"
+	// PeekDuration 临时显示密码的时长
+	PeekDuration = 5 * time.Second
+	// QRDisplayDuration 二维码对话框自动关闭的时长
+	QRDisplayDuration = 30 * time.Second
+	// MaskChar 密码掩码字符
+	MaskChar = "•"
 )
 
 func InitMainWindow() fyne.Window {
 	app := fyne.CurrentApp()
 	mainWindow := app.NewWindow("")
-	i18n.RegisterRefresher(i18n.MainWindowTitleKey, func(value string) {
+	// 每个窗口使用自己的翻译器,窗口中的控件注册到它
+	localizer := i18n.NewLocalizer()
+	localizer.Register(i18n.MainWindowTitleKey, func(value string) {
 		mainWindow.SetTitle(value)
 	})
 	var canvasObjectsToRefresh []fyne.CanvasObject
 	callback := func() []fyne.CanvasObject {
 		return canvasObjectsToRefresh
 	}
+	settings := newDefaultSettings()
 	// 密码生成Tab
-	mainTabItem := initMainTabContent(mainWindow)
+	mainTabItem := initMainTabContent(mainWindow, localizer, settings)
 	canvasObjectsToRefresh = append(canvasObjectsToRefresh, mainTabItem)
 	passwdTab := container.NewTabItemWithIcon("", theme.HomeIcon(), mainTabItem)
-	i18n.RegisterRefresher(i18n.PassWdTabTitleKey, func(value string) {
+	localizer.Register(i18n.PassWdTabTitleKey, func(value string) {
 		passwdTab.Text = value
 	})
+	// 派生密码Tab
+	deriveTabItem := initDeriveTabContent(mainWindow, localizer, settings)
+	canvasObjectsToRefresh = append(canvasObjectsToRefresh, deriveTabItem)
+	deriveTab := container.NewTabItemWithIcon("", theme.AccountIcon(), deriveTabItem)
+	localizer.Register(i18n.DeriveTabTitleKey, func(value string) {
+		deriveTab.Text = value
+	})
+	// 口令Tab
+	passphraseTabItem := initPassphraseTabContent(mainWindow, localizer, settings)
+	canvasObjectsToRefresh = append(canvasObjectsToRefresh, passphraseTabItem)
+	passphraseTab := container.NewTabItemWithIcon("", theme.ListIcon(), passphraseTabItem)
+	localizer.Register(i18n.PassphraseTabTitleKey, func(value string) {
+		passphraseTab.Text = value
+	})
+	// Wi-Fi Tab
+	wifiTabItem := initWifiTabContent(mainWindow, localizer, settings)
+	canvasObjectsToRefresh = append(canvasObjectsToRefresh, wifiTabItem)
+	wifiTab := container.NewTabItemWithIcon("", theme.ComputerIcon(), wifiTabItem)
+	localizer.Register(i18n.WifiTabTitleKey, func(value string) {
+		wifiTab.Text = value
+	})
 	// 设置Tab
-	settingTabItem, tls := initSettingTabContent(callback, mainWindow)
+	settingTabItem, tls := initSettingTabContent(callback, localizer, mainWindow, settings)
 	canvasObjectsToRefresh = append(canvasObjectsToRefresh, settingTabItem)
 	settingTab := container.NewTabItemWithIcon("", theme.SettingsIcon(), settingTabItem)
-	i18n.RegisterRefresher(i18n.SettingTabTitleKey, func(value string) {
+	localizer.Register(i18n.SettingTabTitleKey, func(value string) {
 		settingTab.Text = value
 	})
-	tabs := container.NewAppTabs(passwdTab, settingTab)
+	tabs := container.NewAppTabs(passwdTab, deriveTab, passphraseTab, wifiTab, settingTab)
 	// 选中的Tab进行刷新
 	tabs.OnSelected = func(ti *container.TabItem) {
 		ti.Content.Refresh()
@@ -56,33 +92,77 @@ func InitMainWindow() fyne.Window {
 	mainWindow.SetContent(tabs)
 	// 选择默认主题和语言
 	tls.selectDefault()
+	if settings.profilesErr != nil {
+		showError(settings.profilesErr, mainWindow, localizer)
+	}
+	if settings.historyErr != nil {
+		showError(settings.historyErr, mainWindow, localizer)
+	}
+	appConfig()
+	if appConfigErr != nil {
+		showError(appConfigErr, mainWindow, localizer)
+	}
+	if err := i18n.UserBundleError(); err != nil {
+		showError(err, mainWindow, localizer)
+	}
+	if err := pm.Default().Err(); err != nil {
+		showError(err, mainWindow, localizer)
+	}
 	return mainWindow
 }
 
-func initMainTabContent(w fyne.Window) fyne.CanvasObject {
+func initMainTabContent(w fyne.Window, localizer *i18n.Localizer, settings *settings) fyne.CanvasObject {
 	bindings := newDefaultBindings()
-	// 随机密码输出
-	passwdOutputEntry := widget.NewEntryWithData(bindings.passwdOutputBinding)
+	// 随机密码输出(掩码显示,右侧眼睛图标切换明文)
+	passwdOutputEntry := widget.NewPasswordEntry()
+	passwdOutputEntry.Bind(bindings.passwdOutputBinding)
+	// 等宽字体区分 0/O 和 l/I/1
+	passwdOutputEntry.TextStyle = fyne.TextStyle{Monospace: true}
+	passwdOutputEntry.Password = getBoolBindingValue(settings.alwaysMask)
+	bindings.passwdOutputEntry = passwdOutputEntry
+	bindings.alwaysMask = settings.alwaysMask
+	// 分组着色显示,复制时仍使用原始密码
+	passwdChunkedText := newChunkedText()
+	renderPasswdChunked := func() {
+		size := getIntBindingValue(settings.chunkSize)
+		if size <= 0 {
+			passwdChunkedText.Hide()
+			return
+		}
+		renderChunked(passwdChunkedText, getStringBindingValue(bindings.passwdOutputBinding), size,
+			passwdOutputEntry.Password)
+		passwdChunkedText.Show()
+	}
+	bindings.passwdOutputBinding.AddListener(binding.NewDataListener(renderPasswdChunked))
+	settings.chunkSize.AddListener(binding.NewDataListener(renderPasswdChunked))
+	phoneticPanel, renderPhonetic := newPhoneticPanel(localizer, bindings.passwdOutputBinding, func() bool {
+		return passwdOutputEntry.Password
+	})
+	// 掩码状态变动时重新显示分组和读音
+	renderMasked := func() {
+		renderPasswdChunked()
+		renderPhonetic()
+	}
+	setMaskToggle(passwdOutputEntry, renderMasked)
 	// 密码强度
 	passwdStrengthLabel := widget.NewLabel("")
-	i18n.RegisterRefresher(i18n.PasswdStrengthLabelKey, func(value string) {
+	localizer.Register(i18n.PasswdStrengthLabelKey, func(value string) {
 		passwdStrengthLabel.Text = value
 	})
-	bindings.passwdStrengthInfo = canvas.NewText("", nil)
-	bindings.passwdStrengthCost = canvas.NewText("", color.NRGBA{R: 0xff, G: 0x98, B: 0x00, A: 0xff})
+	bindings.passwdStrength = newStrengthView(localizer)
 	// 密码长度
 	passwdLengthLabel := widget.NewLabel("")
-	i18n.RegisterRefresher(i18n.PasswdLengthLabelKey, func(value string) {
+	localizer.Register(i18n.PasswdLengthLabelKey, func(value string) {
 		passwdLengthLabel.Text = value
 	})
 	bindings.passwdLengthInfo = canvas.NewText("0", color.NRGBA{R: 0xf4, G: 0x43, B: 0x36, A: 0xff})
 	// 监听密码长度变动
 	bindings.passwdLengthBinding.AddListener(binding.NewDataListener(func() {
-		generatePassword(w, bindings)
+		generatePassword(w, localizer, bindings)
 	}))
 	passwdLengthSlide := widget.NewSliderWithData(0, 64, bindings.passwdLengthBinding)
 	passwdLengthSlideLabel := widget.NewLabel("")
-	i18n.RegisterRefresher(i18n.PasswdLengthSlideLabelKey, func(value string) {
+	localizer.Register(i18n.PasswdLengthSlideLabelKey, func(value string) {
 		passwdLengthSlideLabel.Text = value
 	})
 	passwdLengthSlide.Step = 1
@@ -105,45 +185,75 @@ func initMainTabContent(w fyne.Window) fyne.CanvasObject {
 			_ = bindings.passwdLengthBinding.Set(64)
 		}))
 	pslc := container.NewGridWithColumns(2,
-		container.NewHBox(passwdStrengthLabel, bindings.passwdStrengthInfo, bindings.passwdStrengthCost),
+		container.NewHBox(passwdStrengthLabel, bindings.passwdStrength.info, bindings.passwdStrength.cost),
 		container.New(layout.NewFormLayout(), passwdLengthLabel, bindings.passwdLengthInfo),
 	)
 	plc := container.NewGridWithColumns(2, container.New(layout.NewFormLayout(), passwdLengthSlideLabel,
 		passwdLengthSlide), passwdLengthButtons)
 	// 选项
-	numberCheck := newCheckWidget("", i18n.NumberCheckLabelKey, func(check bool) {
+	numberCheck := newCheckWidget(localizer, "", i18n.NumberCheckLabelKey, func(check bool) {
 		_ = bindings.enableNumber.Set(check)
-		generatePassword(w, bindings)
+		generatePassword(w, localizer, bindings)
 	}, getBoolBindingValue(bindings.enableNumber))
-	lowercaseCheck := newCheckWidget("", i18n.LowercaseCheckLabelKey, func(check bool) {
+	lowercaseCheck := newCheckWidget(localizer, "", i18n.LowercaseCheckLabelKey, func(check bool) {
 		_ = bindings.enableLowercase.Set(check)
-		generatePassword(w, bindings)
+		generatePassword(w, localizer, bindings)
 	}, getBoolBindingValue(bindings.enableLowercase))
-	uppercaseCheck := newCheckWidget("", i18n.UppercaseCheckLabelKey, func(check bool) {
+	uppercaseCheck := newCheckWidget(localizer, "", i18n.UppercaseCheckLabelKey, func(check bool) {
 		_ = bindings.enableUppercase.Set(check)
-		generatePassword(w, bindings)
+		generatePassword(w, localizer, bindings)
 	}, getBoolBindingValue(bindings.enableUppercase))
-	duplicateCheck := newCheckWidget("", i18n.DuplicateCheckLabelKey, func(check bool) {
+	duplicateCheck := newCheckWidget(localizer, "", i18n.DuplicateCheckLabelKey, func(check bool) {
 		_ = bindings.enableDuplicate.Set(check)
-		generatePassword(w, bindings)
+		generatePassword(w, localizer, bindings)
 	}, getBoolBindingValue(bindings.enableDuplicate))
 	checkGroup := container.New(layout.NewGridLayout(4), numberCheck, lowercaseCheck, uppercaseCheck, duplicateCheck)
+	// 内置字符类
+	classChecks := newCharClassChecks(localizer, bindings.classes, func() {
+		generatePassword(w, localizer, bindings)
+	})
+	classCheckGroup := container.New(layout.NewGridLayout(len(classChecks)))
+	for _, check := range classChecks {
+		classCheckGroup.Add(check)
+	}
 	// 包含特殊字符
-	includeSpecialCharSetForm := newCharSetEntryContainer("", i18n.IncludeSpecialCharSetFormLabelKey, bindings.includeSpecialCharSet)
+	includeSpecialCharSetForm := newCharSetEntryContainer(localizer, "", i18n.IncludeSpecialCharSetFormLabelKey, bindings.includeSpecialCharSet)
 	// 排除特殊字符
-	excludeSpecialCharSetForm := newCharSetEntryContainer("", i18n.ExcludeSpecialCharSetFormLabelKey, bindings.excludeSpecialCharSet)
+	excludeSpecialCharSetForm := newCharSetEntryContainer(localizer, "", i18n.ExcludeSpecialCharSetFormLabelKey, bindings.excludeSpecialCharSet)
 	// 功能按钮组
-	copyButton := newOptionButtonWidget("", i18n.CopyButtonLabelKey, theme.ContentCopyIcon(), func() {
+	copyButton := newOptionButtonWidget(localizer, "", i18n.CopyButtonLabelKey, theme.ContentCopyIcon(), func() {
 		value, _ := bindings.passwdOutputBinding.Get()
-		w.Clipboard().SetContent(value)
+		copyToClipboard(w, value)
+	})
+	// 选中的历史记录,二维码优先显示选中的记录
+	var selectedHistoryItem *historyRecordItem
+	var historyRecordSlice []*historyRecordItem
+	// 配置了 history.path 时载入保存的历史记录
+	if settings.history != nil {
+		for _, entry := range settings.history.Entries() {
+			historyRecordSlice = append(historyRecordSlice, &historyRecordItem{password: entry.Password, created: entry.Created,
+				revealed: !getBoolBindingValue(settings.alwaysMask)})
+		}
+	}
+	qrButton := newOptionButtonWidget(localizer, "", i18n.QRButtonLabelKey, theme.ViewFullScreenIcon(), func() {
+		for _, historyItem := range historyRecordSlice {
+			if historyItem == selectedHistoryItem {
+				showQRDialog(w, localizer, historyItem.password)
+				return
+			}
+		}
+		showQRDialog(w, localizer, getStringBindingValue(bindings.passwdOutputBinding))
 	})
-	generateButton := newOptionButtonWidget("", i18n.GenerateButtonLabelKey, theme.NavigateNextIcon(), func() {
-		generatePassword(w, bindings)
+	peekButton := newOptionButtonWidget(localizer, "", i18n.PeekButtonLabelKey, theme.VisibilityIcon(), func() {
+		peekEntry(passwdOutputEntry, settings.alwaysMask, renderMasked)
 	})
-	resetButton := newOptionButtonWidget("", i18n.ResetButtonLabelKey, theme.ViewRefreshIcon(), func() {
+	generateButton := newOptionButtonWidget(localizer, "", i18n.GenerateButtonLabelKey, theme.NavigateNextIcon(), func() {
+		generatePassword(w, localizer, bindings)
+	})
+	resetTo := func(conf *gen.PasswdGenConf) {
 		application := fyne.CurrentApp()
 		application.Preferences().SetBool("__Resetting__", true)
-		resetBindings(bindings)
+		resetBindings(bindings, conf)
 		numberCheck.Checked = getBoolBindingValue(bindings.enableNumber)
 		numberCheck.Refresh()
 		lowercaseCheck.Checked = getBoolBindingValue(bindings.enableLowercase)
@@ -152,24 +262,48 @@ func initMainTabContent(w fyne.Window) fyne.CanvasObject {
 		uppercaseCheck.Refresh()
 		duplicateCheck.Checked = getBoolBindingValue(bindings.enableDuplicate)
 		duplicateCheck.Refresh()
+		refreshCharClassChecks(classChecks, bindings.classes)
 		application.Preferences().SetBool("__Resetting__", false)
+	}
+	// 重置为用户保存的默认配置
+	resetButton := newOptionButtonWidget(localizer, "", i18n.ResetButtonLabelKey, theme.ViewRefreshIcon(), func() {
+		resetTo(loadPasswdGenConf(prefUserDefaultConfPrefix))
+	})
+	// 保存当前配置为用户默认配置
+	saveDefaultButton := newOptionButtonWidget(localizer, "", i18n.SaveDefaultButtonLabelKey, theme.DocumentSaveIcon(), func() {
+		savePasswdGenConf(prefUserDefaultConfPrefix, bindingsToPasswdGenConf(bindings))
 	})
-	optionButtonGroup := container.New(layout.NewGridLayout(3), copyButton, generateButton, resetButton)
+	// 恢复出厂设置时重置为配置文件中的默认配置
+	settings.addFactoryResetListener(func() {
+		resetTo(newConfiguredPasswdGenConf())
+	})
+	// 切换配置
+	profileSelectContainer := newProfileSelectContainer(settings.profiles, func(conf *gen.PasswdGenConf) {
+		resetTo(conf)
+		generatePassword(w, localizer, bindings)
+	}, w, localizer)
+	optionButtonGroup := container.New(layout.NewGridLayout(6), copyButton, qrButton, peekButton, generateButton,
+		resetButton, saveDefaultButton)
 	passwdGenBox := container.NewVBox(
+		profileSelectContainer,
 		passwdOutputEntry,
+		passwdChunkedText,
+		phoneticPanel,
 		pslc,
 		plc,
 		checkGroup,
+		classCheckGroup,
 		includeSpecialCharSetForm,
 		excludeSpecialCharSetForm,
 		optionButtonGroup,
+		newHashPanel(w, localizer, bindings.passwdOutputBinding),
+		newSharePanel(w, localizer, bindings.passwdOutputBinding),
 	)
 	passwdGenCard := widget.NewCard("", "", passwdGenBox)
-	i18n.RegisterRefresher(i18n.PasswdGenCardTitleKey, func(value string) {
+	localizer.Register(i18n.PasswdGenCardTitleKey, func(value string) {
 		passwdGenCard.Title = value
 	})
 	passwdGenBorder := container.NewBorder(passwdGenCard, nil, nil, nil)
-	var historyRecordSlice []*historyRecordItem
 	// 历史记录
 	historyRecordTable := widget.NewTable(func() (int, int) {
 		return len(historyRecordSlice), 4
@@ -179,6 +313,10 @@ func initMainTabContent(w fyne.Window) fyne.CanvasObject {
 			widget.NewToolbar(
 				widget.NewToolbarAction(theme.ContentCopyIcon(), func() {
 
+				}),
+				widget.NewToolbarSeparator(),
+				widget.NewToolbarAction(theme.VisibilityIcon(), func() {
+
 				}),
 				widget.NewToolbarSeparator(),
 				widget.NewToolbarAction(theme.DeleteIcon(), func() {
@@ -189,11 +327,23 @@ func initMainTabContent(w fyne.Window) fyne.CanvasObject {
 	}, func(cellId widget.TableCellID, object fyne.CanvasObject) {
 
 	})
+	// 已生成的密码数量
+	historyCountLabel := widget.NewLabel("")
+	historyCountLabel.Hide()
+	historyCount := localizer.RegisterTemplate(i18n.HistoryCountLabelKey, historyCountData(0), 0, func(value string) {
+		historyCountLabel.SetText(value)
+	})
+	refreshHistory := func() {
+		historyRecordTable.Refresh()
+		historyCount.SetTemplateData(historyCountData(len(historyRecordSlice)), len(historyRecordSlice))
+	}
 	historyRecordTable.UpdateCell = func(cellId widget.TableCellID, object fyne.CanvasObject) {
 		historyRecordLabel := object.(*fyne.Container).Objects[0].(*widget.Label)
 		historyRecordToolbar := object.(*fyne.Container).Objects[1].(*widget.Toolbar)
 		historyRecordLabel.Show()
 		historyRecordToolbar.Hide()
+		// 密码列使用等宽字体
+		historyRecordLabel.TextStyle.Monospace = cellId.Col == 1
 		row := cellId.Row
 		switch cellId.Col {
 		case 0:
@@ -205,7 +355,13 @@ func initMainTabContent(w fyne.Window) fyne.CanvasObject {
 			if l > 0 && row < l {
 				historyItem := historyRecordSlice[row]
 				if historyItem != nil {
-					historyRecordLabel.SetText(historyItem.password)
+					size := getIntBindingValue(settings.chunkSize)
+					// 始终掩码时历史记录默认掩码显示,逐行切换明文
+					if !historyItem.revealed {
+						historyRecordLabel.SetText(gen.FormatChunked(maskPassword(historyItem.password), size))
+					} else {
+						historyRecordLabel.SetText(gen.FormatChunked(historyItem.password, size))
+					}
 				}
 			}
 		case 2:
@@ -214,11 +370,11 @@ func initMainTabContent(w fyne.Window) fyne.CanvasObject {
 			if l > 0 && row < l {
 				historyItem := historyRecordSlice[row]
 				if historyItem != nil {
-					historyRecordLabel.SetText(historyItem.createTime)
+					historyRecordLabel.SetText(historyItem.created.Format(historyTimeLayout))
 				}
 			}
 		case 3:
-			// 拷贝\删除按钮
+			// 拷贝\显示\删除按钮
 			historyRecordLabel.Hide()
 			historyRecordToolbar.Show()
 			historyRecordToolbarItems := historyRecordToolbar.Items
@@ -227,54 +383,131 @@ func initMainTabContent(w fyne.Window) fyne.CanvasObject {
 				if l > 0 && row < l {
 					historyItem := historyRecordSlice[row]
 					if historyItem != nil && historyItem.password != "" {
-						w.Clipboard().SetContent(historyItem.password)
+						copyToClipboard(w, historyItem.password)
 					}
 				}
 			})
-			historyRecordToolbarItems[2] = widget.NewToolbarAction(theme.DeleteIcon(), func() {
+			visibilityIcon := theme.VisibilityIcon()
+			if l := len(historyRecordSlice); l > 0 && row < l && historyRecordSlice[row] != nil && historyRecordSlice[row].revealed {
+				visibilityIcon = theme.VisibilityOffIcon()
+			}
+			historyRecordToolbarItems[2] = widget.NewToolbarAction(visibilityIcon, func() {
+				l := len(historyRecordSlice)
+				if l > 0 && row < l {
+					historyItem := historyRecordSlice[row]
+					if historyItem != nil {
+						// 始终掩码时同时只明文显示一行
+						revealed := !historyItem.revealed
+						if revealed && getBoolBindingValue(settings.alwaysMask) {
+							for _, item := range historyRecordSlice {
+								item.revealed = false
+							}
+						}
+						historyItem.revealed = revealed
+						historyRecordTable.Refresh()
+						// 始终掩码时明文仅临时显示
+						if revealed && getBoolBindingValue(settings.alwaysMask) {
+							time.AfterFunc(PeekDuration, func() {
+								if !getBoolBindingValue(settings.alwaysMask) {
+									return
+								}
+								historyItem.revealed = false
+								historyRecordTable.Refresh()
+							})
+						}
+					}
+				}
+			})
+			historyRecordToolbarItems[4] = widget.NewToolbarAction(theme.DeleteIcon(), func() {
 				l := len(historyRecordSlice)
 				if l > 0 && row < l {
 					historyRecordSlice = removeHistoryItemByIndex(historyRecordSlice, row)
-					historyRecordTable.Refresh()
+					refreshHistory()
+					if settings.history != nil {
+						if err := settings.history.Remove(row); err != nil {
+							showError(err, w, localizer)
+						}
+					}
 				}
 			})
 			historyRecordToolbar.Refresh()
 		}
 	}
+	historyRecordTable.OnSelected = func(cellId widget.TableCellID) {
+		if cellId.Row >= 0 && cellId.Row < len(historyRecordSlice) {
+			selectedHistoryItem = historyRecordSlice[cellId.Row]
+		}
+	}
+	historyRecordTable.OnUnselected = func(widget.TableCellID) {
+		selectedHistoryItem = nil
+	}
 	historyRecordTable.SetColumnWidth(0, 32)
 	historyRecordTable.SetColumnWidth(1, 200)
 	historyRecordTable.SetColumnWidth(2, 160)
+	historyRecordTable.SetColumnWidth(3, 120)
 	historyRecordScroll := container.NewVScroll(historyRecordTable)
 	historyRecordScroll.SetMinSize(fyne.NewSize(0, 200))
 	historyRecordTable.Hide()
-	historyCheck := newCheckWidget("", i18n.HistoryCheckLabelKey, func(check bool) {
+	historyCheck := newCheckWidget(localizer, "", i18n.HistoryCheckLabelKey, func(check bool) {
 		if !check {
 			historyRecordScroll.Hide()
 			historyRecordTable.Hide()
+			historyCountLabel.Hide()
 			historyRecordSlice = nil
-			historyRecordTable.Refresh()
+			refreshHistory()
 			historyRecordScroll.Refresh()
+			// 关闭历史记录时同时删除保存的记录
+			if settings.history != nil {
+				if err := settings.history.Clear(); err != nil {
+					showError(err, w, localizer)
+				}
+			}
 		} else {
 			historyRecordScroll.Show()
 			historyRecordTable.Show()
+			historyCountLabel.Show()
 		}
 	}, false)
+	// 保存的历史记录默认开启
+	if settings.history != nil {
+		historyCheck.SetChecked(true)
+		refreshHistory()
+	}
 	historyBox := container.NewVBox(
-		historyCheck,
+		container.NewHBox(historyCheck, layout.NewSpacer(), historyCountLabel),
 		historyRecordScroll,
 	)
 	historyCard := widget.NewCard("", "", historyBox)
-	i18n.RegisterRefresher(i18n.HistoryCardTitleKey, func(value string) {
+	localizer.Register(i18n.HistoryCardTitleKey, func(value string) {
 		historyCard.Title = value
 	})
 	historyBorder := container.NewBorder(historyCard, nil, nil, nil)
+	// 监听始终掩码设置变动
+	settings.alwaysMask.AddListener(binding.NewDataListener(func() {
+		mask := getBoolBindingValue(settings.alwaysMask)
+		passwdOutputEntry.Password = mask
+		passwdOutputEntry.Refresh()
+		renderMasked()
+		for _, historyItem := range historyRecordSlice {
+			historyItem.revealed = !mask
+		}
+		historyRecordTable.Refresh()
+	}))
+	settings.chunkSize.AddListener(binding.NewDataListener(func() {
+		historyRecordTable.Refresh()
+	}))
 	go func() {
 		for {
 			select {
 			case hri := <-bindings.historyRecordChan:
 				if historyCheck.Checked {
 					historyRecordSlice = append(historyRecordSlice, hri)
-					historyRecordTable.Refresh()
+					refreshHistory()
+					if settings.history != nil {
+						if err := settings.history.Add(history.Entry{Password: hri.password, Created: hri.created}); err != nil {
+							showError(err, w, localizer)
+						}
+					}
 				}
 			}
 		}
@@ -282,65 +515,131 @@ func initMainTabContent(w fyne.Window) fyne.CanvasObject {
 	return container.NewVBox(passwdGenBorder, historyBorder)
 }
 
-func initSettingTabContent(callback func() []fyne.CanvasObject, _ fyne.Window) (fyne.CanvasObject, *themeLangSelector) {
+func initSettingTabContent(callback func() []fyne.CanvasObject, localizer *i18n.Localizer, w fyne.Window, settings *settings) (fyne.CanvasObject, *themeLangSelector) {
 	app := fyne.CurrentApp()
-	themeGroup := widget.NewRadioGroup([]string{
-		"Dark(暗黑)",
-		"Light(白色)",
-	}, func(st string) {
-		app.Settings().SetTheme(&pm.SelectableTheme{Theme: st})
-	})
-	themeGroup.Required = true
-	themeGroup.Horizontal = true
-	langGroup := widget.NewRadioGroup([]string{
-		"zh_CN(中文)",
-		"en_US(英文)",
-	}, func(lang string) {
-		canvasLocalizer := &i18n.CanvasLocalizer{
-			Lang: lang,
+	// 内置主题和用户目录中的主题
+	picker := newThemePicker(w, localizer)
+	themeGroup := picker.themeSelect
+	// 语言列表来自内置和用户目录中的语言包
+	var langOptions []string
+	for _, lang := range i18n.Languages() {
+		langOptions = append(langOptions, lang.Option())
+	}
+	langGroup := widget.NewSelect(langOptions, func(lang string) {
+		// 语言包缺失消息时仍然切换,其余错误保持当前语言
+		if err := localizer.SwitchLang(lang, callback()); err != nil {
+			showError(err, w, localizer)
+			var mme *i18n.MissingMessagesError
+			if !errors.As(err, &mme) {
+				return
+			}
 		}
-		canvasLocalizer.SwitchLang(callback())
+		app.Preferences().SetString(prefLanguageKey, lang)
+		_ = settings.language.Set(lang)
 	})
-	langGroup.Horizontal = true
-	langGroup.Required = true
 	themeForm := widget.NewFormItem("", themeGroup)
-	i18n.RegisterRefresher(i18n.SettingThemeFormTitleKey, func(value string) {
+	localizer.Register(i18n.SettingThemeFormTitleKey, func(value string) {
 		themeForm.Text = value
 	})
+	themePreviewForm := widget.NewFormItem("", picker.preview)
+	localizer.Register(i18n.ThemePreviewFormLabelKey, func(value string) {
+		themePreviewForm.Text = value
+	})
 	langForm := widget.NewFormItem("", langGroup)
-	i18n.RegisterRefresher(i18n.SettingLangFormTitleKey, func(value string) {
+	localizer.Register(i18n.SettingLangFormTitleKey, func(value string) {
 		langForm.Text = value
 	})
-	uiForm := container.NewVBox(widget.NewForm(themeForm, langForm))
+	// 密码分组显示
+	chunkSizeSelect := widget.NewSelect(ChunkSizes, func(value string) {
+		size, _ := strconv.Atoi(value)
+		_ = settings.chunkSize.Set(size)
+	})
+	chunkSizeSelect.SetSelected(strconv.Itoa(getIntBindingValue(settings.chunkSize)))
+	chunkSizeForm := widget.NewFormItem("", chunkSizeSelect)
+	localizer.Register(i18n.ChunkSizeFormLabelKey, func(value string) {
+		chunkSizeForm.Text = value
+	})
+	// 界面字体和字号
+	fontForm := widget.NewFormItem("", container.NewBorder(nil, nil, nil, picker.fontButtons, picker.fontLabel))
+	localizer.Register(i18n.SettingFontFormLabelKey, func(value string) {
+		fontForm.Text = value
+	})
+	fontScaleForm := widget.NewFormItem("", picker.fontScaleSelect)
+	localizer.Register(i18n.FontScaleFormLabelKey, func(value string) {
+		fontScaleForm.Text = value
+	})
+	// 主题导入导出按钮紧跟主题预览
+	themeButtonsForm := widget.NewFormItem("", picker.buttons)
+	uiForm := widget.NewForm(themeForm, themePreviewForm, themeButtonsForm, langForm, chunkSizeForm, fontForm, fontScaleForm)
 	appearanceCard := widget.NewCard("", "", uiForm)
-	i18n.RegisterRefresher(i18n.SettingAppearanceCardTitleKey, func(value string) {
+	localizer.Register(i18n.SettingAppearanceCardTitleKey, func(value string) {
 		appearanceCard.Title = value
 	})
-	box := container.NewVBox(appearanceCard)
-	return container.NewBorder(box, nil, nil, nil), &themeLangSelector{themeGroup: themeGroup,
-		langGroup: langGroup}
+	// 隐私
+	alwaysMaskCheck := newCheckWidget(localizer, "", i18n.AlwaysMaskCheckLabelKey, func(check bool) {
+		_ = settings.alwaysMask.Set(check)
+	}, getBoolBindingValue(settings.alwaysMask))
+	privacyCard := widget.NewCard("", "", container.NewVBox(alwaysMaskCheck))
+	localizer.Register(i18n.SettingPrivacyCardTitleKey, func(value string) {
+		privacyCard.Title = value
+	})
+	tls := &themeLangSelector{themePicker: picker, langGroup: langGroup}
+	// 恢复出厂设置
+	factoryResetConfirmLabel := newLabelWidget(localizer, "", i18n.FactoryResetConfirmMessageKey)
+	factoryResetButton := newOptionButtonWidget(localizer, "", i18n.FactoryResetButtonLabelKey, theme.ViewRefreshIcon(), func() {
+		dialog.ShowCustomConfirm(localize(localizer, i18n.FactoryResetConfirmTitleKey),
+			localize(localizer, i18n.ConfirmButtonLabelKey), localize(localizer, i18n.CancelButtonLabelKey),
+			factoryResetConfirmLabel, func(ok bool) {
+				if !ok {
+					return
+				}
+				factoryResetPreferences()
+				settings.factoryReset()
+				alwaysMaskCheck.SetChecked(getBoolBindingValue(settings.alwaysMask))
+				chunkSizeSelect.SetSelected(strconv.Itoa(getIntBindingValue(settings.chunkSize)))
+				picker.resetFont()
+				picker.selectTheme(DefaultTheme)
+				langGroup.SetSelected(defaultLanguageOption(langGroup.Options))
+			}, w)
+	})
+	resetCard := widget.NewCard("", "", container.NewVBox(factoryResetButton))
+	localizer.Register(i18n.SettingResetCardTitleKey, func(value string) {
+		resetCard.Title = value
+	})
+	// 配置管理
+	profileCard := initProfileCard(settings.profiles, w, localizer)
+	box := container.NewVBox(appearanceCard, profileCard, privacyCard, resetCard)
+	return container.NewBorder(box, nil, nil, nil), tls
+}
+
+func newLabelWidget(texts textRegistrar, label string, i18nKey i18n.MessageId) *widget.Label {
+	labelWidget := widget.NewLabel(label)
+	texts.Register(i18nKey, func(value string) {
+		labelWidget.Text = value
+	})
+	return labelWidget
 }
 
-func newCharSetEntryContainer(label string, i18nKey i18n.MessageId, dataBinding binding.String) *fyne.Container {
+func newCharSetEntryContainer(texts textRegistrar, label string, i18nKey i18n.MessageId, dataBinding binding.String) *fyne.Container {
 	labelWidget := widget.NewLabel(label)
-	i18n.RegisterRefresher(i18nKey, func(value string) {
+	texts.Register(i18nKey, func(value string) {
 		labelWidget.Text = value
 	})
 	entry := widget.NewEntryWithData(dataBinding)
 	return container.New(layout.NewFormLayout(), labelWidget, entry)
 }
 
-func newOptionButtonWidget(label string, i18nKey i18n.MessageId, icon fyne.Resource, callback func()) *widget.Button {
+func newOptionButtonWidget(texts textRegistrar, label string, i18nKey i18n.MessageId, icon fyne.Resource, callback func()) *widget.Button {
 	button := widget.NewButtonWithIcon(label, icon, callback)
-	i18n.RegisterRefresher(i18nKey, func(value string) {
+	texts.Register(i18nKey, func(value string) {
 		button.Text = value
 	})
 	return button
 }
 
-func newCheckWidget(label string, i18nKey i18n.MessageId, callback func(check bool), checked bool) *widget.Check {
+func newCheckWidget(texts textRegistrar, label string, i18nKey i18n.MessageId, callback func(check bool), checked bool) *widget.Check {
 	check := widget.NewCheck(label, callback)
-	i18n.RegisterRefresher(i18nKey, func(value string) {
+	texts.Register(i18nKey, func(value string) {
 		check.Text = value
 	})
 	check.Checked = checked
@@ -352,6 +651,11 @@ func getBoolBindingValue(b binding.Bool) bool {
 	return r
 }
 
+func getIntBindingValue(i binding.Int) int {
+	value, _ := i.Get()
+	return value
+}
+
 func getUint8FromFloat64BindingValue(f binding.Float) uint8 {
 	r, _ := f.Get()
 	return uint8(r)
@@ -368,10 +672,12 @@ func getStringBindingValue(s binding.String) string {
 type bindings struct {
 	// 生成密码输出的数据绑定
 	passwdOutputBinding binding.String
-	// 密码强度描述
-	passwdStrengthInfo *canvas.Text
-	// 密码强度破解耗时
-	passwdStrengthCost *canvas.Text
+	// 生成密码输出框
+	passwdOutputEntry *widget.Entry
+	// 是否始终掩码显示
+	alwaysMask binding.Bool
+	// 密码强度描述及破解耗时
+	passwdStrength *strengthView
 	// 密码长度描述
 	passwdLengthInfo *canvas.Text
 	// 密码长度数据绑定
@@ -388,17 +694,75 @@ type bindings struct {
 	includeSpecialCharSet binding.String
 	// 排除的特殊字符集
 	excludeSpecialCharSet binding.String
+	// 启用的内置字符类,逗号分隔
+	classes binding.String
 	// 历史记录Channel
 	historyRecordChan chan *historyRecordItem
 }
 
 type historyRecordItem struct {
-	password   string
-	createTime string
+	password string
+	created  time.Time
+	// 是否明文显示,开启始终掩码时默认掩码
+	revealed bool
+}
+
+// settings 全局设置
+type settings struct {
+	// 是否始终掩码显示密码
+	alwaysMask binding.Bool
+	// 密码分组显示的每组字符数,0表示不分组
+	chunkSize binding.Int
+	// 当前界面语言选项,例如 zh(中文)
+	language binding.String
+	// 恢复出厂设置监听器
+	factoryResetListeners []func()
+	// 命名的密码生成配置
+	profiles *profile.Store
+	// 配置加载错误
+	profilesErr error
+	// 保存在 history.path 中的历史记录,未配置时为nil
+	history *history.Store
+	// 历史记录加载错误
+	historyErr error
+}
+
+func newDefaultSettings() *settings {
+	settings := &settings{
+		alwaysMask: binding.NewBool(),
+		chunkSize:  binding.NewInt(),
+		language:   binding.NewString(),
+	}
+	_ = settings.alwaysMask.Set(preferences().BoolWithFallback(prefAlwaysMaskKey, false))
+	settings.alwaysMask.AddListener(binding.NewDataListener(func() {
+		preferences().SetBool(prefAlwaysMaskKey, getBoolBindingValue(settings.alwaysMask))
+	}))
+	_ = settings.chunkSize.Set(preferences().IntWithFallback(prefChunkSizeKey, 0))
+	settings.chunkSize.AddListener(binding.NewDataListener(func() {
+		preferences().SetInt(prefChunkSizeKey, getIntBindingValue(settings.chunkSize))
+	}))
+	settings.profiles, settings.profilesErr = profile.OpenDefault()
+	if path := appConfig().HistoryPath; path != "" {
+		settings.history, settings.historyErr = history.Open(path)
+	}
+	return settings
+}
+
+func (s *settings) addFactoryResetListener(listener func()) {
+	s.factoryResetListeners = append(s.factoryResetListeners, listener)
+}
+
+func (s *settings) factoryReset() {
+	_ = s.alwaysMask.Set(false)
+	_ = s.chunkSize.Set(0)
+	for _, listener := range s.factoryResetListeners {
+		listener()
+	}
 }
 
 func newDefaultBindings() *bindings {
-	defaultConf := gen.NewDefaultPasswdGenConf()
+	// 恢复上次保存的配置
+	defaultConf := loadPasswdGenConf(prefCurrentConfPrefix)
 	bindings := &bindings{
 		passwdOutputBinding:   binding.NewString(),
 		passwdLengthBinding:   binding.NewFloat(),
@@ -408,6 +772,7 @@ func newDefaultBindings() *bindings {
 		enableDuplicate:       binding.NewBool(),
 		includeSpecialCharSet: binding.NewString(),
 		excludeSpecialCharSet: binding.NewString(),
+		classes:               binding.NewString(),
 		historyRecordChan:     make(chan *historyRecordItem),
 	}
 	_ = bindings.passwdLengthBinding.Set(float64(defaultConf.Length))
@@ -417,11 +782,37 @@ func newDefaultBindings() *bindings {
 	_ = bindings.enableDuplicate.Set(defaultConf.EnableDuplicate)
 	_ = bindings.includeSpecialCharSet.Set(defaultConf.IncludeSpecialCharSet)
 	_ = bindings.excludeSpecialCharSet.Set(defaultConf.ExcludeSpecialCharSet)
+	_ = bindings.classes.Set(strings.Join(defaultConf.Classes, ","))
+	// 配置变动时保存
+	saveListener := binding.NewDataListener(func() {
+		savePasswdGenConf(prefCurrentConfPrefix, bindingsToPasswdGenConf(bindings))
+	})
+	bindings.passwdLengthBinding.AddListener(saveListener)
+	bindings.enableNumber.AddListener(saveListener)
+	bindings.enableLowercase.AddListener(saveListener)
+	bindings.enableUppercase.AddListener(saveListener)
+	bindings.enableDuplicate.AddListener(saveListener)
+	bindings.includeSpecialCharSet.AddListener(saveListener)
+	bindings.excludeSpecialCharSet.AddListener(saveListener)
+	bindings.classes.AddListener(saveListener)
 	return bindings
 }
 
-func resetBindings(bindings *bindings) {
-	defaultConf := gen.NewDefaultPasswdGenConf()
+func bindingsToPasswdGenConf(bindings *bindings) *gen.PasswdGenConf {
+	classes, _ := gen.ParseCharClasses(getStringBindingValue(bindings.classes))
+	return &gen.PasswdGenConf{
+		Length:                getUint8FromFloat64BindingValue(bindings.passwdLengthBinding),
+		EnableNumber:          getBoolBindingValue(bindings.enableNumber),
+		EnableLowercase:       getBoolBindingValue(bindings.enableLowercase),
+		EnableUppercase:       getBoolBindingValue(bindings.enableUppercase),
+		EnableDuplicate:       getBoolBindingValue(bindings.enableDuplicate),
+		IncludeSpecialCharSet: getStringBindingValue(bindings.includeSpecialCharSet),
+		ExcludeSpecialCharSet: getStringBindingValue(bindings.excludeSpecialCharSet),
+		Classes:               classes,
+	}
+}
+
+func resetBindings(bindings *bindings, defaultConf *gen.PasswdGenConf) {
 	_ = bindings.passwdLengthBinding.Set(float64(defaultConf.Length))
 	_ = bindings.passwdOutputBinding.Set("")
 	_ = bindings.enableNumber.Set(defaultConf.EnableNumber)
@@ -430,52 +821,89 @@ func resetBindings(bindings *bindings) {
 	_ = bindings.enableDuplicate.Set(defaultConf.EnableDuplicate)
 	_ = bindings.includeSpecialCharSet.Set(defaultConf.IncludeSpecialCharSet)
 	_ = bindings.excludeSpecialCharSet.Set(defaultConf.ExcludeSpecialCharSet)
-	if bindings.passwdStrengthInfo != nil {
-		bindings.passwdStrengthInfo.Text = ""
-	}
-	if bindings.passwdStrengthCost != nil {
-		bindings.passwdStrengthCost.Text = ""
+	_ = bindings.classes.Set(strings.Join(defaultConf.Classes, ","))
+	if bindings.passwdStrength != nil {
+		bindings.passwdStrength.set(nil)
 	}
 }
 
-func generatePassword(w fyne.Window, bindings *bindings) {
+func generatePassword(w fyne.Window, localizer *i18n.Localizer, bindings *bindings) {
 	application := fyne.CurrentApp()
 	// 主窗口初始化才处理密码生成
 	if application.Preferences().Bool("__MainWindowInit__") && !application.Preferences().Bool("__Resetting__") {
-		result, err := gen.GeneratePassword(&gen.PasswdGenConf{
-			Length:                getUint8FromFloat64BindingValue(bindings.passwdLengthBinding),
-			EnableNumber:          getBoolBindingValue(bindings.enableNumber),
-			EnableLowercase:       getBoolBindingValue(bindings.enableLowercase),
-			EnableUppercase:       getBoolBindingValue(bindings.enableUppercase),
-			EnableDuplicate:       getBoolBindingValue(bindings.enableDuplicate),
-			IncludeSpecialCharSet: getStringBindingValue(bindings.includeSpecialCharSet),
-			ExcludeSpecialCharSet: getStringBindingValue(bindings.excludeSpecialCharSet),
-		})
+		result, err := gen.GeneratePassword(bindingsToPasswdGenConf(bindings))
 		if err != nil {
-			dialog.ShowError(err, w)
+			showError(err, w, localizer)
 		} else {
+			// 始终掩码时新密码重新掩码,先于设置密码以便分组和读音按掩码显示
+			if bindings.passwdOutputEntry != nil && getBoolBindingValue(bindings.alwaysMask) && !bindings.passwdOutputEntry.Password {
+				bindings.passwdOutputEntry.Password = true
+				bindings.passwdOutputEntry.Refresh()
+			}
 			_ = bindings.passwdOutputBinding.Set(result.Password)
-			bindings.historyRecordChan <- &historyRecordItem{password: result.Password, createTime: time.Now().Format("2006-01-02 15:04:05")}
-			bindings.passwdStrengthInfo.Text = result.StrengthInfo
-			bindings.passwdStrengthInfo.Color = result.StrengthColor
-			bindings.passwdStrengthInfo.Refresh()
-			// CostInfo => StrengthInt
-			bindings.passwdStrengthCost.Text = strings.Join([]string{result.CostInfo, " => ",
-				strconv.FormatFloat(result.StrengthInt, 'f', 4, 64)}, "")
-			bindings.passwdStrengthCost.Color = result.CostColor
-			bindings.passwdStrengthCost.Refresh()
+			bindings.historyRecordChan <- &historyRecordItem{password: result.Password, created: time.Now(),
+				revealed: !getBoolBindingValue(bindings.alwaysMask)}
+			bindings.passwdStrength.set(result)
 		}
 	}
 }
 
 type themeLangSelector struct {
-	themeGroup *widget.RadioGroup
-	langGroup  *widget.RadioGroup
+	themePicker *themePicker
+	langGroup   *widget.Select
 }
 
+// selectDefault 选择上次保存的主题和语言,没有保存时使用默认值
 func (tls *themeLangSelector) selectDefault() {
-	tls.themeGroup.SetSelected(DefaultTheme)
-	tls.langGroup.SetSelected(DefaultLanguage)
+	tls.themePicker.selectSaved()
+	defaultLanguage := defaultLanguageOption(tls.langGroup.Options)
+	tls.langGroup.SetSelected(languageOption(preferences().String(prefLanguageKey), tls.langGroup.Options, defaultLanguage))
+}
+
+// copyToClipboard 复制到剪贴板,配置了超时时间时到期后清空
+func copyToClipboard(w fyne.Window, value string) {
+	w.Clipboard().SetContent(value)
+	timeout := appConfig().ClipboardTimeout
+	if timeout > 0 && value != "" {
+		time.AfterFunc(timeout, func() {
+			// 剪贴板内容已被替换时不清空
+			if w.Clipboard().Content() == value {
+				w.Clipboard().SetContent("")
+			}
+		})
+	}
+}
+
+// peekEntry 临时明文显示密码,PeekDuration后仍为始终掩码时恢复掩码,掩码状态变动时通知listeners
+func peekEntry(entry *widget.Entry, alwaysMask binding.Bool, listeners ...func()) {
+	if !entry.Password {
+		return
+	}
+	entry.Password = false
+	entry.Refresh()
+	for _, listener := range listeners {
+		listener()
+	}
+	time.AfterFunc(PeekDuration, func() {
+		// 期间关闭了始终掩码时保持明文
+		if !getBoolBindingValue(alwaysMask) {
+			return
+		}
+		entry.Password = true
+		entry.Refresh()
+		for _, listener := range listeners {
+			listener()
+		}
+	})
+}
+
+func maskPassword(passwd string) string {
+	return strings.Repeat(MaskChar, uniseg.GraphemeClusterCount(passwd))
+}
+
+// historyCountData 已生成密码数量文本的模板参数
+func historyCountData(count int) map[string]interface{} {
+	return map[string]interface{}{"Count": count}
 }
 
 func removeHistoryItemByIndex(slice []*historyRecordItem, i int) []*historyRecordItem {
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"passwdgen/hasher"
+	"passwdgen/i18n"
+	"sync"
+)
+
+// newHashPanel 密码哈希面板,按格式计算并复制当前密码的哈希
+func newHashPanel(w fyne.Window, localizer *i18n.Localizer, passwdBinding binding.String) fyne.CanvasObject {
+	hashLabels := make(map[hasher.Format]*widget.Label, len(hasher.Formats))
+	rows := container.NewVBox()
+	for _, format := range hasher.Formats {
+		format := format
+		hashLabel := widget.NewLabel("")
+		hashLabel.Wrapping = fyne.TextTruncate
+		hashLabels[format] = hashLabel
+		copyButton := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() {
+			if hashLabel.Text != "" {
+				copyToClipboard(w, hashLabel.Text)
+			}
+		})
+		formatLabel := widget.NewLabelWithStyle(string(format), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		rows.Add(container.NewBorder(nil, nil, formatLabel, copyButton, hashLabel))
+	}
+	progress := widget.NewProgressBarInfinite()
+	progress.Stop()
+	progress.Hide()
+	var mu sync.Mutex
+	// 每次计算的序号,密码变动后丢弃过期的结果
+	var seq int
+	clearHashes := func() {
+		for _, hashLabel := range hashLabels {
+			hashLabel.SetText("")
+		}
+	}
+	computeButton := newOptionButtonWidget(localizer, "", i18n.HashComputeButtonLabelKey, theme.ConfirmIcon(), nil)
+	computeButton.OnTapped = func() {
+		password := getStringBindingValue(passwdBinding)
+		if password == "" {
+			return
+		}
+		mu.Lock()
+		seq++
+		current := seq
+		mu.Unlock()
+		clearHashes()
+		computeButton.Disable()
+		progress.Show()
+		progress.Start()
+		go func() {
+			results, err := hasher.HashAll(password, hasher.Formats, appConfig().Hash)
+			mu.Lock()
+			stale := current != seq
+			mu.Unlock()
+			progress.Stop()
+			progress.Hide()
+			computeButton.Enable()
+			if stale {
+				return
+			}
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			for _, result := range results {
+				hashLabels[result.Format].SetText(result.Hash)
+			}
+		}()
+	}
+	// 密码变动后哈希失效
+	passwdBinding.AddListener(binding.NewDataListener(func() {
+		mu.Lock()
+		seq++
+		mu.Unlock()
+		clearHashes()
+	}))
+	hashItem := widget.NewAccordionItem("", container.NewVBox(computeButton, progress, rows))
+	hashAccordion := widget.NewAccordion(hashItem)
+	localizer.Register(i18n.HashPanelTitleKey, func(value string) {
+		hashItem.Title = value
+	})
+	return hashAccordion
+}
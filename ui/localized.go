@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"errors"
+	"fyne.io/fyne/v2/dialog"
+	"passwdgen/i18n"
+	"sync"
+)
+
+// textRegistrar 注册随语言切换刷新的文本。窗口中的控件注册到窗口的 *i18n.Localizer,
+// 对话框中的控件注册到 *dialogTexts,对话框关闭时一并取消注册
+type textRegistrar interface {
+	Register(messageId i18n.MessageId, m func(value string)) *i18n.Registration
+}
+
+// dialogTexts 对话框中注册的文本
+type dialogTexts struct {
+	localizer     *i18n.Localizer
+	mu            sync.Mutex
+	registrations []*i18n.Registration
+}
+
+func newDialogTexts(localizer *i18n.Localizer) *dialogTexts {
+	return &dialogTexts{localizer: localizer}
+}
+
+func (dt *dialogTexts) Register(messageId i18n.MessageId, m func(value string)) *i18n.Registration {
+	r := dt.localizer.Register(messageId, m)
+	dt.mu.Lock()
+	dt.registrations = append(dt.registrations, r)
+	dt.mu.Unlock()
+	return r
+}
+
+// unregisterOnClosed 对话框关闭时取消注册的文本,避免重复打开对话框后刷新回调越积越多
+func (dt *dialogTexts) unregisterOnClosed(d dialog.Dialog) {
+	d.SetOnClosed(func() {
+		dt.mu.Lock()
+		registrations := dt.registrations
+		dt.registrations = nil
+		dt.mu.Unlock()
+		for _, r := range registrations {
+			r.Unregister()
+		}
+	})
+}
+
+// localize 按窗口的当前语言翻译对话框标题和按钮等创建后不再更新的文本
+func localize(localizer *i18n.Localizer, messageId i18n.MessageId) string {
+	return localizer.Localize(messageId, nil, nil)
+}
+
+// localizeError 按窗口的当前语言翻译带错误码的错误,用于错误对话框和输入框的校验提示
+func localizeError(localizer *i18n.Localizer, err error) error {
+	return errors.New(i18n.LocalizeErrorWith(err, func(messageId i18n.MessageId) (string, bool) {
+		if localizer.Lang() == "" {
+			return "", false
+		}
+		return localize(localizer, messageId), true
+	}))
+}
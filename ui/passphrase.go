@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"passwdgen/gen"
+	"passwdgen/i18n"
+	"passwdgen/wordlist"
+)
+
+// initPassphraseTabContent 由词表单词组成的口令,词表默认跟随界面语言,也可单独选择
+func initPassphraseTabContent(w fyne.Window, localizer *i18n.Localizer, settings *settings) fyne.CanvasObject {
+	reg := wordlist.Default()
+	savedWordList := preferences().StringWithFallback(prefPassphraseWordListKey, "")
+	wordListSelect := widget.NewSelect(reg.Names(), nil)
+	followLangCheck := newCheckWidget(localizer, "", i18n.PassphraseFollowLangCheckLabelKey, nil, savedWordList == "")
+	wordEntropyInfo := widget.NewLabel("")
+	// 跟随界面语言时按当前语言选择词表
+	syncWordList := func() {
+		if followLangCheck.Checked {
+			wordListSelect.SetSelected(wordlist.ForLanguage(getStringBindingValue(settings.language)))
+			wordListSelect.Disable()
+		} else {
+			wordListSelect.Enable()
+		}
+	}
+	wordListSelect.OnChanged = func(name string) {
+		if list, err := reg.Get(name); err == nil {
+			wordEntropyInfo.SetText(fmt.Sprintf("%d × %.2f bit", len(list.Words), list.Entropy()))
+		}
+		if !followLangCheck.Checked {
+			preferences().SetString(prefPassphraseWordListKey, name)
+		}
+	}
+	followLangCheck.OnChanged = func(follow bool) {
+		if follow {
+			preferences().RemoveValue(prefPassphraseWordListKey)
+		} else {
+			preferences().SetString(prefPassphraseWordListKey, wordListSelect.Selected)
+		}
+		syncWordList()
+	}
+	wordListSelect.SetSelected(loadOption(prefPassphraseWordListKey, wordListSelect.Options, wordlist.English))
+	settings.language.AddListener(binding.NewDataListener(syncWordList))
+	wordsInfo := widget.NewLabel(fmt.Sprint(gen.DefaultPassphraseWords))
+	wordsSlide := widget.NewSlider(gen.MinPassphraseWords, gen.MaxPassphraseWords)
+	wordsSlide.Step = 1
+	wordsSlide.SetValue(gen.DefaultPassphraseWords)
+	wordsSlide.OnChanged = func(value float64) {
+		wordsInfo.SetText(fmt.Sprintf("%0.0f", value))
+	}
+	digitsInfo := widget.NewLabel("0")
+	digitsSlide := widget.NewSlider(0, gen.MaxPassphraseDigits)
+	digitsSlide.Step = 1
+	digitsSlide.OnChanged = func(value float64) {
+		digitsInfo.SetText(fmt.Sprintf("%0.0f", value))
+	}
+	separatorEntry := widget.NewEntry()
+	separatorEntry.SetText(gen.DefaultPassphraseSeparator)
+	capitalizeCheck := newCheckWidget(localizer, "", i18n.PassphraseCapitalizeCheckLabelKey, nil, false)
+	passphraseEntry := widget.NewPasswordEntry()
+	passphraseEntry.Password = getBoolBindingValue(settings.alwaysMask)
+	strength := newStrengthView(localizer)
+	generateButton := newOptionButtonWidget(localizer, "", i18n.GenerateButtonLabelKey, theme.NavigateNextIcon(), func() {
+		result, err := gen.GeneratePassphrase(&gen.PassphraseConf{
+			Words:      int(wordsSlide.Value),
+			WordList:   wordListSelect.Selected,
+			Separator:  separatorEntry.Text,
+			Capitalize: capitalizeCheck.Checked,
+			Digits:     int(digitsSlide.Value),
+		})
+		if err != nil {
+			showError(err, w, localizer)
+			return
+		}
+		passphraseEntry.SetText(result.Password)
+		if getBoolBindingValue(settings.alwaysMask) && !passphraseEntry.Password {
+			passphraseEntry.Password = true
+			passphraseEntry.Refresh()
+		}
+		strength.set(result)
+	})
+	copyButton := newOptionButtonWidget(localizer, "", i18n.CopyButtonLabelKey, theme.ContentCopyIcon(), func() {
+		if passphraseEntry.Text != "" {
+			copyToClipboard(w, passphraseEntry.Text)
+		}
+	})
+	peekButton := newOptionButtonWidget(localizer, "", i18n.PeekButtonLabelKey, theme.VisibilityIcon(), func() {
+		peekEntry(passphraseEntry, settings.alwaysMask)
+	})
+	settings.addFactoryResetListener(func() {
+		followLangCheck.SetChecked(true)
+		wordsSlide.SetValue(gen.DefaultPassphraseWords)
+		digitsSlide.SetValue(0)
+		separatorEntry.SetText(gen.DefaultPassphraseSeparator)
+		capitalizeCheck.SetChecked(false)
+		passphraseEntry.SetText("")
+		strength.set(nil)
+	})
+	form := container.New(layout.NewFormLayout(),
+		newLabelWidget(localizer, "", i18n.PassphraseWordListFormLabelKey),
+		container.NewBorder(nil, nil, nil, container.NewHBox(wordEntropyInfo, followLangCheck), wordListSelect),
+		newLabelWidget(localizer, "", i18n.PassphraseWordsFormLabelKey), container.NewBorder(nil, nil, nil, wordsInfo, wordsSlide),
+		newLabelWidget(localizer, "", i18n.PassphraseDigitsFormLabelKey), container.NewBorder(nil, nil, nil, digitsInfo, digitsSlide),
+		newLabelWidget(localizer, "", i18n.PassphraseSeparatorFormLabelKey),
+		container.NewBorder(nil, nil, nil, capitalizeCheck, separatorEntry),
+		newLabelWidget(localizer, "", i18n.PassphraseFormLabelKey), passphraseEntry,
+		newLabelWidget(localizer, "", i18n.PasswdStrengthLabelKey), container.NewHBox(strength.info, strength.cost),
+	)
+	passphraseBox := container.NewVBox(
+		form,
+		container.New(layout.NewGridLayout(3), generateButton, copyButton, peekButton),
+	)
+	passphraseCard := widget.NewCard("", "", passphraseBox)
+	localizer.Register(i18n.PassphraseCardTitleKey, func(value string) {
+		passphraseCard.Title = value
+	})
+	// 校验失败的用户词表不可选,提示具体原因
+	if err := reg.Err(); err != nil {
+		showError(err, w, localizer)
+	}
+	return container.NewBorder(passphraseCard, nil, nil, nil)
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/widget"
+	"passwdgen/i18n"
+	"passwdgen/phonetic"
+	"sync"
+)
+
+// newPhoneticPanel 密码的读音拼写面板,便于电话中口述密码。masked 返回真时只显示掩码,
+// 返回的函数在掩码状态变动时重新显示
+func newPhoneticPanel(localizer *i18n.Localizer, passwdBinding binding.String, masked func() bool) (fyne.CanvasObject, func()) {
+	var mu sync.Mutex
+	words := make(map[i18n.MessageId]string)
+	spellingLabel := widget.NewLabel("")
+	spellingLabel.Wrapping = fyne.TextWrapWord
+	render := func() {
+		password := getStringBindingValue(passwdBinding)
+		if masked() {
+			spellingLabel.SetText(maskPassword(password))
+			return
+		}
+		mu.Lock()
+		tokens := phonetic.Spell(password, func(messageId i18n.MessageId) string {
+			return words[messageId]
+		})
+		mu.Unlock()
+		spellingLabel.SetText(phonetic.Format(tokens))
+	}
+	// 切换语言时更新读法
+	for _, key := range phonetic.Keys() {
+		key := key
+		localizer.Register(key, func(value string) {
+			mu.Lock()
+			words[key] = value
+			mu.Unlock()
+			render()
+		})
+	}
+	passwdBinding.AddListener(binding.NewDataListener(render))
+	phoneticItem := widget.NewAccordionItem("", spellingLabel)
+	localizer.Register(i18n.PhoneticPanelTitleKey, func(value string) {
+		phoneticItem.Title = value
+	})
+	return widget.NewAccordion(phoneticItem), render
+}
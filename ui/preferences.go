@@ -0,0 +1,216 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"passwdgen/config"
+	"passwdgen/gen"
+	"passwdgen/i18n"
+	"strings"
+	"sync"
+)
+
+const (
+	// 主题
+	prefThemeKey = "theme"
+	// 语言
+	prefLanguageKey = "language"
+	// 是否始终掩码显示
+	prefAlwaysMaskKey = "alwaysMask"
+	// 密码分组显示的每组字符数
+	prefChunkSizeKey = "chunkSize"
+	// 用户选择的界面字体文件路径,为空时使用内置字体
+	prefFontPathKey = "fontPath"
+	// 界面字号缩放比例
+	prefFontScaleKey = "fontScale"
+	// 口令使用的词表,为空时跟随界面语言
+	prefPassphraseWordListKey = "passphraseWordList"
+	// 当前的密码生成配置
+	prefCurrentConfPrefix = "conf.current."
+	// 用户自定义的默认密码生成配置
+	prefUserDefaultConfPrefix = "conf.default."
+	// 派生密码使用的配置,与密码生成页的配置互不影响
+	prefDeriveConfPrefix = "conf.derive."
+)
+
+const (
+	prefConfLengthKey                = "length"
+	prefConfEnableNumberKey          = "enableNumber"
+	prefConfEnableLowercaseKey       = "enableLowercase"
+	prefConfEnableUppercaseKey       = "enableUppercase"
+	prefConfEnableDuplicateKey       = "enableDuplicate"
+	prefConfIncludeSpecialCharSetKey = "includeSpecialCharSet"
+	prefConfExcludeSpecialCharSetKey = "excludeSpecialCharSet"
+	prefConfClassesKey               = "classes"
+)
+
+var prefConfKeys = []string{
+	prefConfLengthKey,
+	prefConfEnableNumberKey,
+	prefConfEnableLowercaseKey,
+	prefConfEnableUppercaseKey,
+	prefConfEnableDuplicateKey,
+	prefConfIncludeSpecialCharSetKey,
+	prefConfExcludeSpecialCharSetKey,
+	prefConfClassesKey,
+}
+
+var (
+	appConfigOnce sync.Once
+	appConfigErr  error
+	appConfigured *config.Config
+)
+
+func preferences() fyne.Preferences {
+	return fyne.CurrentApp().Preferences()
+}
+
+// appConfig 读取配置文件和环境变量中的默认配置,配置无效时使用内置默认值
+func appConfig() *config.Config {
+	appConfigOnce.Do(func() {
+		appConfigured, appConfigErr = config.Load()
+		if appConfigErr != nil {
+			appConfigured = config.Default()
+		}
+	})
+	return appConfigured
+}
+
+// newConfiguredPasswdGenConf 返回配置文件中的默认密码生成配置
+func newConfiguredPasswdGenConf() *gen.PasswdGenConf {
+	conf := *appConfig().Gen
+	return &conf
+}
+
+// loadPasswdGenConf 读取指定前缀下保存的密码生成配置,缺失的字段使用配置文件中的默认值
+func loadPasswdGenConf(prefix string) *gen.PasswdGenConf {
+	return loadPasswdGenConfWithFallback(prefix, newConfiguredPasswdGenConf())
+}
+
+// loadDerivePasswdGenConf 读取派生密码的配置。首次使用时沿用密码生成页的当前配置并保存,
+// 使之前派生的密码保持不变,之后修改密码生成页不再影响派生结果
+func loadDerivePasswdGenConf() *gen.PasswdGenConf {
+	if preferences().Int(prefDeriveConfPrefix+prefConfLengthKey) > 0 {
+		return loadPasswdGenConf(prefDeriveConfPrefix)
+	}
+	conf := loadPasswdGenConf(prefCurrentConfPrefix)
+	savePasswdGenConf(prefDeriveConfPrefix, conf)
+	return conf
+}
+
+// loadPasswdGenConfWithFallback 读取指定前缀下保存的密码生成配置,缺失的字段使用conf中的值
+func loadPasswdGenConfWithFallback(prefix string, conf *gen.PasswdGenConf) *gen.PasswdGenConf {
+	prefs := preferences()
+	length := prefs.IntWithFallback(prefix+prefConfLengthKey, int(conf.Length))
+	if length > 0 && length <= 255 {
+		conf.Length = uint8(length)
+	}
+	conf.EnableNumber = prefs.BoolWithFallback(prefix+prefConfEnableNumberKey, conf.EnableNumber)
+	conf.EnableLowercase = prefs.BoolWithFallback(prefix+prefConfEnableLowercaseKey, conf.EnableLowercase)
+	conf.EnableUppercase = prefs.BoolWithFallback(prefix+prefConfEnableUppercaseKey, conf.EnableUppercase)
+	conf.EnableDuplicate = prefs.BoolWithFallback(prefix+prefConfEnableDuplicateKey, conf.EnableDuplicate)
+	conf.IncludeSpecialCharSet = prefs.StringWithFallback(prefix+prefConfIncludeSpecialCharSetKey, conf.IncludeSpecialCharSet)
+	conf.ExcludeSpecialCharSet = prefs.StringWithFallback(prefix+prefConfExcludeSpecialCharSetKey, conf.ExcludeSpecialCharSet)
+	classes, err := gen.ParseCharClasses(prefs.StringWithFallback(prefix+prefConfClassesKey, strings.Join(conf.Classes, ",")))
+	if err == nil {
+		conf.Classes = classes
+	}
+	return conf
+}
+
+// savePasswdGenConf 保存密码生成配置到指定前缀下
+func savePasswdGenConf(prefix string, conf *gen.PasswdGenConf) {
+	prefs := preferences()
+	prefs.SetInt(prefix+prefConfLengthKey, int(conf.Length))
+	prefs.SetBool(prefix+prefConfEnableNumberKey, conf.EnableNumber)
+	prefs.SetBool(prefix+prefConfEnableLowercaseKey, conf.EnableLowercase)
+	prefs.SetBool(prefix+prefConfEnableUppercaseKey, conf.EnableUppercase)
+	prefs.SetBool(prefix+prefConfEnableDuplicateKey, conf.EnableDuplicate)
+	prefs.SetString(prefix+prefConfIncludeSpecialCharSetKey, conf.IncludeSpecialCharSet)
+	prefs.SetString(prefix+prefConfExcludeSpecialCharSetKey, conf.ExcludeSpecialCharSet)
+	prefs.SetString(prefix+prefConfClassesKey, strings.Join(conf.Classes, ","))
+}
+
+// removePasswdGenConf 移除指定前缀下保存的密码生成配置
+func removePasswdGenConf(prefix string) {
+	prefs := preferences()
+	for _, key := range prefConfKeys {
+		prefs.RemoveValue(prefix + key)
+	}
+}
+
+// languageOption 返回语言(如 zh_CN、zh(中文))对应的语言选项,优先完全相同的语言,其次基础语言相同的语言,没有时返回fallback
+func languageOption(value string, options []string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	lang := optionLanguage(value)
+	for _, option := range options {
+		if optionLanguage(option) == lang {
+			return option
+		}
+	}
+	base := baseLanguage(value)
+	for _, option := range options {
+		if baseLanguage(option) == base {
+			return option
+		}
+	}
+	return fallback
+}
+
+// defaultLanguageOption 返回首次启动使用的语言选项:
+// 配置文件或环境变量中明确配置的语言优先,其次是系统语言环境,最后使用默认语言
+func defaultLanguageOption(options []string) string {
+	fallback := languageOption(DefaultLanguage, options, "")
+	cfg := appConfig()
+	if cfg.Origin("language").Source != config.SourceDefault {
+		return languageOption(cfg.Language, options, fallback)
+	}
+	if lang, ok := i18n.DetectLanguage(); ok {
+		return languageOption(lang.Option(), options, fallback)
+	}
+	return languageOption(cfg.Language, options, fallback)
+}
+
+// optionLanguage 返回选项中的语言标签,例如 zh_CN(中文) 返回 zh-cn
+func optionLanguage(option string) string {
+	if i := strings.Index(option, "("); i >= 0 {
+		option = option[:i]
+	}
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(option), "_", "-"))
+}
+
+// baseLanguage 返回选项中的基础语言,例如 zh_CN(中文) 返回 zh
+func baseLanguage(option string) string {
+	lang := optionLanguage(option)
+	if i := strings.Index(lang, "-"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// loadOption 读取保存的选项值,值不在可选项中时返回fallback
+func loadOption(key string, options []string, fallback string) string {
+	value := preferences().StringWithFallback(key, fallback)
+	for _, option := range options {
+		if option == value {
+			return value
+		}
+	}
+	return fallback
+}
+
+// factoryResetPreferences 清除所有用户设置
+func factoryResetPreferences() {
+	prefs := preferences()
+	prefs.RemoveValue(prefThemeKey)
+	prefs.RemoveValue(prefLanguageKey)
+	prefs.RemoveValue(prefAlwaysMaskKey)
+	prefs.RemoveValue(prefChunkSizeKey)
+	prefs.RemoveValue(prefFontPathKey)
+	prefs.RemoveValue(prefFontScaleKey)
+	prefs.RemoveValue(prefPassphraseWordListKey)
+	removePasswdGenConf(prefCurrentConfPrefix)
+	removePasswdGenConf(prefUserDefaultConfPrefix)
+	removePasswdGenConf(prefDeriveConfPrefix)
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"passwdgen/errcode"
+	"runtime"
+	"strings"
+)
+
+const (
+	// errorPrintUnavailable 系统中没有可用的打印命令
+	errorPrintUnavailable errcode.Code = "print_unavailable"
+	// errorPrintFailed 打印命令执行失败
+	errorPrintFailed errcode.Code = "print_failed"
+)
+
+// 登记错误码对应的提示消息
+func init() {
+	errcode.Register(errorPrintUnavailable, "ErrorPrintUnavailableMessage")
+	errcode.Register(errorPrintFailed, "ErrorPrintFailedMessage")
+}
+
+var printUnavailableError = errcode.New(errorPrintUnavailable)
+var printFailedError = errcode.New(errorPrintFailed)
+
+// printCommand 返回把文本文件发送到默认打印机的命令。
+// Linux、BSD 和 macOS 使用 CUPS 的 lp,换页符处分页;Windows 使用记事本打印
+func printCommand(path string) (*exec.Cmd, error) {
+	name, args := "lp", []string{"--", path}
+	if runtime.GOOS == "windows" {
+		name, args = "notepad", []string{"/p", path}
+	}
+	if _, err := exec.LookPath(name); err != nil {
+		return nil, printUnavailableError
+	}
+	return exec.Command(name, args...), nil
+}
+
+// printText 把文本发送到默认打印机。文本写入只有当前用户可读的临时文件,打印命令返回后删除
+func printText(text string) error {
+	f, err := os.CreateTemp("", "passwdgen-*.txt")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(text); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	cmd, err := printCommand(f.Name())
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && strings.TrimSpace(stderr.String()) != "" {
+			return fmt.Errorf("%w: %s", printFailedError, strings.TrimSpace(stderr.String()))
+		}
+		return fmt.Errorf("%w: %v", printFailedError, err)
+	}
+	return nil
+}
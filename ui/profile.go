@@ -0,0 +1,224 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"io"
+	"passwdgen/gen"
+	"passwdgen/i18n"
+	"passwdgen/profile"
+	"strings"
+)
+
+const profileExportFileName = "passwdgen-profiles.toml"
+
+// newProfileSelectContainer 主界面的配置切换下拉框
+func newProfileSelectContainer(store *profile.Store, onSelected func(conf *gen.PasswdGenConf), w fyne.Window,
+	localizer *i18n.Localizer) *fyne.Container {
+	labelWidget := newLabelWidget(localizer, "", i18n.ProfileFormLabelKey)
+	profileSelect := widget.NewSelect(nil, nil)
+	localizer.Register(i18n.ProfileSelectPlaceholderKey, func(value string) {
+		profileSelect.PlaceHolder = value
+	})
+	if store == nil {
+		profileSelect.Disable()
+		return container.New(layout.NewFormLayout(), labelWidget, profileSelect)
+	}
+	profileSelect.Options = store.Names()
+	profileSelect.OnChanged = func(name string) {
+		if name == "" {
+			return
+		}
+		p, err := store.Get(name)
+		if err != nil {
+			showError(err, w, localizer)
+			return
+		}
+		onSelected(p.Conf)
+	}
+	store.AddChangeListener(func() {
+		refreshProfileOptions(profileSelect, store.Names())
+	})
+	return container.New(layout.NewFormLayout(), labelWidget, profileSelect)
+}
+
+// initProfileCard 设置界面的配置管理
+func initProfileCard(store *profile.Store, w fyne.Window, localizer *i18n.Localizer) *widget.Card {
+	profileCard := widget.NewCard("", "", nil)
+	localizer.Register(i18n.SettingProfileCardTitleKey, func(value string) {
+		profileCard.Title = value
+	})
+	profileSelect := widget.NewSelect(nil, nil)
+	localizer.Register(i18n.ProfileSelectPlaceholderKey, func(value string) {
+		profileSelect.PlaceHolder = value
+	})
+	if store == nil {
+		profileSelect.Disable()
+		profileCard.SetContent(profileSelect)
+		return profileCard
+	}
+	profileSelect.Options = store.Names()
+	store.AddChangeListener(func() {
+		refreshProfileOptions(profileSelect, store.Names())
+	})
+	showNameDialog := func(initial string, callback func(name string) error) {
+		nameEntry := widget.NewEntry()
+		nameEntry.SetText(initial)
+		dialog.ShowForm(localize(localizer, i18n.ProfileNameDialogTitleKey), localize(localizer, i18n.ConfirmButtonLabelKey),
+			localize(localizer, i18n.CancelButtonLabelKey),
+			[]*widget.FormItem{widget.NewFormItem(localize(localizer, i18n.ProfileNameFormLabelKey), nameEntry)},
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				if err := callback(nameEntry.Text); err != nil {
+					showError(err, w, localizer)
+				}
+			}, w)
+	}
+	withSelected := func(callback func(name string)) func() {
+		return func() {
+			if profileSelect.Selected != "" {
+				callback(profileSelect.Selected)
+			}
+		}
+	}
+	// 保存当前配置
+	saveAsButton := newOptionButtonWidget(localizer, "", i18n.ProfileSaveAsButtonLabelKey, theme.DocumentSaveIcon(), func() {
+		showNameDialog(profileSelect.Selected, func(name string) error {
+			if err := store.Save(name, loadPasswdGenConf(prefCurrentConfPrefix)); err != nil {
+				return err
+			}
+			profileSelect.SetSelected(name)
+			return nil
+		})
+	})
+	renameButton := newOptionButtonWidget(localizer, "", i18n.ProfileRenameButtonLabelKey, theme.DocumentCreateIcon(),
+		withSelected(func(selected string) {
+			showNameDialog(selected, func(name string) error {
+				if err := store.Rename(selected, name); err != nil {
+					return err
+				}
+				profileSelect.SetSelected(name)
+				return nil
+			})
+		}))
+	duplicateButton := newOptionButtonWidget(localizer, "", i18n.ProfileDuplicateButtonLabelKey, theme.ContentCopyIcon(),
+		withSelected(func(selected string) {
+			showNameDialog(selected, func(name string) error {
+				if err := store.Duplicate(selected, name); err != nil {
+					return err
+				}
+				profileSelect.SetSelected(name)
+				return nil
+			})
+		}))
+	deleteConfirmLabel := newLabelWidget(localizer, "", i18n.ProfileDeleteConfirmMessageKey)
+	deleteButton := newOptionButtonWidget(localizer, "", i18n.ProfileDeleteButtonLabelKey, theme.DeleteIcon(),
+		withSelected(func(selected string) {
+			dialog.ShowCustomConfirm(localize(localizer, i18n.ProfileDeleteButtonLabelKey),
+				localize(localizer, i18n.ConfirmButtonLabelKey), localize(localizer, i18n.CancelButtonLabelKey),
+				deleteConfirmLabel, func(ok bool) {
+					if !ok {
+						return
+					}
+					if err := store.Delete(selected); err != nil {
+						showError(err, w, localizer)
+					}
+				}, w)
+		}))
+	// 导入配置,有同名配置时确认后覆盖,完成后显示导入的配置
+	var importProfiles func(data []byte, overwrite bool)
+	importProfiles = func(data []byte, overwrite bool) {
+		names, overwritten, err := store.Import(bytes.NewReader(data), overwrite)
+		if errors.Is(err, profile.ProfileExistsError) && len(overwritten) > 0 {
+			confirmLabel := widget.NewLabel(localizer.Localize(i18n.ProfileOverwriteConfirmMessageKey,
+				profileNamesData(overwritten), nil))
+			dialog.ShowCustomConfirm(localize(localizer, i18n.ProfileImportButtonLabelKey),
+				localize(localizer, i18n.ConfirmButtonLabelKey), localize(localizer, i18n.CancelButtonLabelKey),
+				confirmLabel, func(ok bool) {
+					if ok {
+						importProfiles(data, true)
+					}
+				}, w)
+			return
+		}
+		if err != nil {
+			showError(err, w, localizer)
+			return
+		}
+		dialog.ShowInformation(localize(localizer, i18n.ProfileImportButtonLabelKey),
+			localizer.Localize(i18n.ProfileImportedMessageKey, profileNamesData(names), nil), w)
+	}
+	importButton := newOptionButtonWidget(localizer, "", i18n.ProfileImportButtonLabelKey, theme.FolderOpenIcon(), func() {
+		fileOpen := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			data, err := io.ReadAll(reader)
+			reader.Close()
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			importProfiles(data, false)
+		}, w)
+		fileOpen.SetFilter(storage.NewExtensionFileFilter([]string{".toml"}))
+		fileOpen.Show()
+	})
+	exportButton := newOptionButtonWidget(localizer, "", i18n.ProfileExportButtonLabelKey, theme.DownloadIcon(), func() {
+		fileSave := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+			if err := store.Export(writer); err != nil {
+				showError(err, w, localizer)
+			}
+		}, w)
+		fileSave.SetFileName(profileExportFileName)
+		fileSave.Show()
+	})
+	profileCard.SetContent(container.NewVBox(
+		profileSelect,
+		container.New(layout.NewGridLayout(3), saveAsButton, renameButton, duplicateButton),
+		container.New(layout.NewGridLayout(3), deleteButton, importButton, exportButton),
+	))
+	return profileCard
+}
+
+// profileNamesData 配置名称列表文本的模板参数
+func profileNamesData(names []string) map[string]interface{} {
+	return map[string]interface{}{"Names": strings.Join(names, ", ")}
+}
+
+func refreshProfileOptions(profileSelect *widget.Select, names []string) {
+	selected := profileSelect.Selected
+	profileSelect.Options = names
+	found := false
+	for _, name := range names {
+		if name == selected {
+			found = true
+			break
+		}
+	}
+	if !found {
+		profileSelect.ClearSelected()
+	}
+	profileSelect.Refresh()
+}
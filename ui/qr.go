@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"passwdgen/i18n"
+	"passwdgen/qr"
+	"time"
+)
+
+const (
+	qrImageSize    = 256
+	qrExportPrefix = "qrcode"
+)
+
+// setQRImage 把二维码显示到图片控件,内容为空时清空
+func setQRImage(img *canvas.Image, content string) error {
+	if content == "" {
+		img.Image = nil
+		img.Refresh()
+		return nil
+	}
+	code, err := qr.Encode(content)
+	if err != nil {
+		return err
+	}
+	img.Image = code.Image(qrImageSize)
+	img.Refresh()
+	return nil
+}
+
+func newQRImage() *canvas.Image {
+	img := canvas.NewImageFromImage(nil)
+	img.FillMode = canvas.ImageFillContain
+	// 二维码不能平滑缩放,否则难以识别
+	img.ScaleMode = canvas.ImageScalePixels
+	img.SetMinSize(fyne.NewSize(qrImageSize, qrImageSize))
+	return img
+}
+
+// showQRDialog 模态显示密码二维码,超过 QRDisplayDuration 后自动关闭
+func showQRDialog(w fyne.Window, localizer *i18n.Localizer, content string) {
+	if content == "" {
+		return
+	}
+	qrImage := newQRImage()
+	if err := setQRImage(qrImage, content); err != nil {
+		showError(err, w, localizer)
+		return
+	}
+	texts := newDialogTexts(localizer)
+	exportPNGButton := newOptionButtonWidget(texts, "", i18n.QRExportPNGButtonLabelKey, theme.DownloadIcon(), nil)
+	exportSVGButton := newOptionButtonWidget(texts, "", i18n.QRExportSVGButtonLabelKey, theme.DownloadIcon(), nil)
+	d := dialog.NewCustom(localize(localizer, i18n.QRDialogTitleKey), localize(localizer, i18n.ConfirmButtonLabelKey),
+		container.NewVBox(
+			container.NewCenter(qrImage),
+			container.New(layout.NewGridLayout(2), exportPNGButton, exportSVGButton),
+		), w)
+	texts.unregisterOnClosed(d)
+	timer := time.AfterFunc(QRDisplayDuration, d.Hide)
+	d.SetOnClosed(func() {
+		timer.Stop()
+	})
+	exportPNGButton.OnTapped = func() {
+		d.Hide()
+		exportQR(w, localizer, content, qr.FormatPNG)
+	}
+	exportSVGButton.OnTapped = func() {
+		d.Hide()
+		exportQR(w, localizer, content, qr.FormatSVG)
+	}
+	d.Show()
+}
+
+// exportQR 选择文件后导出二维码图片
+func exportQR(w fyne.Window, localizer *i18n.Localizer, content string, format qr.Format) {
+	if content == "" {
+		return
+	}
+	code, err := qr.Encode(content)
+	if err != nil {
+		showError(err, w, localizer)
+		return
+	}
+	fileSave := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil {
+			showError(err, w, localizer)
+			return
+		}
+		if writer == nil {
+			return
+		}
+		defer writer.Close()
+		if err := code.Write(writer, format); err != nil {
+			showError(err, w, localizer)
+		}
+	}, w)
+	fileSave.SetFileName(qrExportPrefix + "." + string(format))
+	fileSave.Show()
+}
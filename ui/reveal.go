@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maskToggle 密码输入框右侧的眼睛图标,切换掩码后通知监听者。
+// fyne 自带的切换按钮没有回调,分组和读音显示无法随之更新
+type maskToggle struct {
+	widget.BaseWidget
+	icon      *canvas.Image
+	entry     *widget.Entry
+	listeners []func()
+}
+
+// setMaskToggle 替换密码输入框的切换按钮,掩码状态由按钮切换时调用listeners
+func setMaskToggle(entry *widget.Entry, listeners ...func()) {
+	toggle := &maskToggle{
+		icon:      canvas.NewImageFromResource(theme.VisibilityOffIcon()),
+		entry:     entry,
+		listeners: listeners,
+	}
+	toggle.ExtendBaseWidget(toggle)
+	entry.ActionItem = toggle
+}
+
+func (t *maskToggle) Tapped(*fyne.PointEvent) {
+	t.entry.Password = !t.entry.Password
+	t.entry.Refresh()
+	for _, listener := range t.listeners {
+		listener()
+	}
+	if c := fyne.CurrentApp().Driver().CanvasForObject(t); c != nil {
+		c.Focus(t.entry)
+	}
+}
+
+func (t *maskToggle) Cursor() desktop.Cursor {
+	return desktop.DefaultCursor
+}
+
+func (t *maskToggle) CreateRenderer() fyne.WidgetRenderer {
+	return &maskToggleRenderer{WidgetRenderer: widget.NewSimpleRenderer(t.icon), toggle: t}
+}
+
+type maskToggleRenderer struct {
+	fyne.WidgetRenderer
+	toggle *maskToggle
+}
+
+func (r *maskToggleRenderer) Layout(size fyne.Size) {
+	iconSize := theme.IconInlineSize()
+	r.toggle.icon.Resize(fyne.NewSize(iconSize, iconSize))
+	r.toggle.icon.Move(fyne.NewPos((size.Width-iconSize)/2, (size.Height-iconSize)/2))
+}
+
+func (r *maskToggleRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(theme.IconInlineSize(), theme.IconInlineSize())
+}
+
+// Refresh 按输入框当前的掩码状态更新图标,peekEntry 等直接修改掩码时也随输入框刷新
+func (r *maskToggleRenderer) Refresh() {
+	if r.toggle.entry.Password {
+		r.toggle.icon.Resource = theme.VisibilityOffIcon()
+	} else {
+		r.toggle.icon.Resource = theme.VisibilityIcon()
+	}
+	r.toggle.icon.Refresh()
+}
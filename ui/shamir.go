@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"passwdgen/i18n"
+	"passwdgen/shamir"
+	"strconv"
+	"strings"
+)
+
+const shareSheetFileName = "shares.txt"
+
+// newSharePanel 秘密分片面板,把当前密码拆分为n个分片或由分片恢复秘密
+func newSharePanel(w fyne.Window, localizer *i18n.Localizer, passwdBinding binding.String) fyne.CanvasObject {
+	splitButton := newOptionButtonWidget(localizer, "", i18n.ShareSplitButtonLabelKey, theme.ContentCutIcon(), func() {
+		password := getStringBindingValue(passwdBinding)
+		if password == "" {
+			return
+		}
+		showSplitDialog(w, localizer, password)
+	})
+	combineButton := newOptionButtonWidget(localizer, "", i18n.ShareCombineButtonLabelKey, theme.ContentPasteIcon(), func() {
+		showCombineDialog(w, localizer)
+	})
+	shareItem := widget.NewAccordionItem("", container.New(layout.NewGridLayout(2), splitButton, combineButton))
+	localizer.Register(i18n.SharePanelTitleKey, func(value string) {
+		shareItem.Title = value
+	})
+	return widget.NewAccordion(shareItem)
+}
+
+// showSplitDialog 输入分片数量和门限后拆分秘密
+func showSplitDialog(w fyne.Window, localizer *i18n.Localizer, secret string) {
+	countEntry := widget.NewEntry()
+	countEntry.SetText("5")
+	countEntry.Validator = shareCountValidator(localizer)
+	thresholdEntry := widget.NewEntry()
+	thresholdEntry.SetText("3")
+	thresholdEntry.Validator = shareCountValidator(localizer)
+	dialog.ShowForm(localize(localizer, i18n.ShareSplitButtonLabelKey), localize(localizer, i18n.ConfirmButtonLabelKey),
+		localize(localizer, i18n.CancelButtonLabelKey), []*widget.FormItem{
+			widget.NewFormItem(localize(localizer, i18n.ShareCountFormLabelKey), countEntry),
+			widget.NewFormItem(localize(localizer, i18n.ShareThresholdFormLabelKey), thresholdEntry),
+		}, func(ok bool) {
+			if !ok {
+				return
+			}
+			n, err := shamir.ParseShareCount(countEntry.Text)
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			k, err := shamir.ParseShareCount(thresholdEntry.Text)
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			shares, err := shamir.Split([]byte(secret), n, k)
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			showShareList(w, localizer, shares)
+		}, w)
+}
+
+// shareCountValidator 校验分片数量和门限,提示按窗口的当前语言显示
+func shareCountValidator(localizer *i18n.Localizer) fyne.StringValidator {
+	return func(value string) error {
+		if _, err := shamir.ParseShareCount(value); err != nil {
+			return localizeError(localizer, err)
+		}
+		return nil
+	}
+}
+
+// showShareList 分片列表,每个分片可单独复制,也可打印或导出为以换页符分页的文本文件
+func showShareList(w fyne.Window, localizer *i18n.Localizer, shares []*shamir.Share) {
+	shareTexts := make([]string, 0, len(shares))
+	rows := container.NewVBox()
+	for _, share := range shares {
+		text := share.String()
+		shareTexts = append(shareTexts, text)
+		shareLabel := widget.NewLabel(text)
+		shareLabel.Wrapping = fyne.TextTruncate
+		copyButton := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() {
+			copyToClipboard(w, text)
+		})
+		indexLabel := widget.NewLabelWithStyle(strconv.Itoa(int(share.Index)), fyne.TextAlignLeading,
+			fyne.TextStyle{Bold: true})
+		rows.Add(container.NewBorder(nil, nil, indexLabel, copyButton, shareLabel))
+	}
+	texts := newDialogTexts(localizer)
+	copyAllButton := newOptionButtonWidget(texts, "", i18n.ShareCopyAllButtonLabelKey, theme.ContentCopyIcon(), func() {
+		copyToClipboard(w, strings.Join(shareTexts, "\n"))
+	})
+	exportButton := newOptionButtonWidget(texts, "", i18n.ShareExportButtonLabelKey, theme.DocumentSaveIcon(), func() {
+		fileSave := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+			if _, err := writer.Write([]byte(shareSheet(localizer, shares))); err != nil {
+				showError(err, w, localizer)
+			}
+		}, w)
+		fileSave.SetFileName(shareSheetFileName)
+		fileSave.Show()
+	})
+	var printButton *widget.Button
+	printButton = newOptionButtonWidget(texts, "", i18n.SharePrintButtonLabelKey, theme.DocumentPrintIcon(), func() {
+		printButton.Disable()
+		go func() {
+			defer printButton.Enable()
+			if err := printText(shareSheet(localizer, shares)); err != nil {
+				showError(err, w, localizer)
+			}
+		}()
+	})
+	content := container.NewBorder(nil, container.New(layout.NewGridLayout(3), copyAllButton, printButton,
+		exportButton), nil, nil, container.NewVScroll(rows))
+	d := dialog.NewCustom(localize(localizer, i18n.ShareListDialogTitleKey), localize(localizer, i18n.ConfirmButtonLabelKey),
+		content, w)
+	texts.unregisterOnClosed(d)
+	d.Resize(fyne.NewSize(560, 400))
+	d.Show()
+}
+
+// shareSheet 打印和导出的文本,分片之间以换页符分隔,打印时每页一个分片,便于分发给不同的保管人
+func shareSheet(localizer *i18n.Localizer, shares []*shamir.Share) string {
+	pages := make([]string, 0, len(shares))
+	for _, share := range shares {
+		header := localizer.Localize(i18n.ShareSheetPageHeaderKey, map[string]interface{}{
+			"Index":     share.Index,
+			"Count":     len(shares),
+			"Threshold": share.Threshold,
+		}, nil)
+		pages = append(pages, fmt.Sprintf("%s\n\n%s\n", header, share))
+	}
+	return strings.Join(pages, "\f")
+}
+
+// showCombineDialog 粘贴分片(每行一个)后恢复秘密
+func showCombineDialog(w fyne.Window, localizer *i18n.Localizer) {
+	sharesEntry := widget.NewMultiLineEntry()
+	sharesEntry.SetMinRowsVisible(5)
+	title := localize(localizer, i18n.ShareCombineButtonLabelKey)
+	dialog.ShowForm(title, localize(localizer, i18n.ConfirmButtonLabelKey), localize(localizer, i18n.CancelButtonLabelKey),
+		[]*widget.FormItem{
+			widget.NewFormItem(localize(localizer, i18n.ShareCombineFormLabelKey), sharesEntry),
+		}, func(ok bool) {
+			if !ok {
+				return
+			}
+			shares, err := shamir.ParseShares(strings.Split(sharesEntry.Text, "\n"))
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			secret, err := shamir.Combine(shares)
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			secretEntry := widget.NewPasswordEntry()
+			secretEntry.SetText(string(secret))
+			copyButton := widget.NewButtonWithIcon("", theme.ContentCopyIcon(), func() {
+				copyToClipboard(w, secretEntry.Text)
+			})
+			texts := newDialogTexts(localizer)
+			d := dialog.NewCustom(title, localize(localizer, i18n.ConfirmButtonLabelKey), container.New(
+				layout.NewFormLayout(), newLabelWidget(texts, "", i18n.ShareSecretFormLabelKey),
+				container.NewBorder(nil, nil, nil, copyButton, secretEntry)), w)
+			texts.unregisterOnClosed(d)
+			d.Show()
+		}, w)
+}
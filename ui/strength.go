@@ -0,0 +1,85 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/dialog"
+	"passwdgen/gen"
+	"passwdgen/i18n"
+	pm "passwdgen/theme"
+	"strconv"
+	"sync"
+)
+
+// strengthView 强度等级和破解时间,语言切换时按当前结果重新显示
+type strengthView struct {
+	mu     sync.Mutex
+	info   *canvas.Text
+	cost   *canvas.Text
+	result *gen.PasswdGenResult
+	// 消息ID到当前语言文本的映射
+	texts map[i18n.MessageId]string
+}
+
+func newStrengthView(localizer *i18n.Localizer) *strengthView {
+	sv := &strengthView{
+		info:  canvas.NewText("", nil),
+		cost:  canvas.NewText("", nil),
+		texts: make(map[i18n.MessageId]string),
+	}
+	// 切换主题或系统亮暗设置变化时按新的颜色重新显示
+	settingsChange := make(chan fyne.Settings)
+	fyne.CurrentApp().Settings().AddChangeListener(settingsChange)
+	go func() {
+		for range settingsChange {
+			sv.render()
+		}
+	}()
+	for _, messageId := range i18n.StrengthMessageIds() {
+		messageId := messageId
+		localizer.Register(messageId, func(value string) {
+			sv.mu.Lock()
+			sv.texts[messageId] = value
+			sv.mu.Unlock()
+			sv.render()
+		})
+	}
+	return sv
+}
+
+// set 显示新的结果,nil表示清空
+func (sv *strengthView) set(result *gen.PasswdGenResult) {
+	sv.mu.Lock()
+	sv.result = result
+	sv.mu.Unlock()
+	sv.render()
+}
+
+func (sv *strengthView) render() {
+	sv.mu.Lock()
+	result := sv.result
+	var info, cost string
+	if result != nil {
+		info = sv.texts[i18n.StrengthMessageId(result.Strength)]
+		// CostInfo => StrengthInt
+		if messageId, ok := i18n.StrengthCostMessageId(result.Strength); ok {
+			cost = sv.texts[messageId] + " => "
+		}
+		cost += strconv.FormatFloat(result.StrengthInt, 'f', 4, 64)
+	}
+	sv.mu.Unlock()
+	sv.info.Text = info
+	sv.cost.Text = cost
+	if result != nil {
+		appSettings := fyne.CurrentApp().Settings()
+		sv.info.Color = pm.StrengthColor(appSettings.Theme(), appSettings.ThemeVariant(), result.Strength)
+		sv.cost.Color = pm.CostColor(appSettings.Theme(), appSettings.ThemeVariant(), result.Strength)
+	}
+	sv.info.Refresh()
+	sv.cost.Refresh()
+}
+
+// showError 显示错误对话框,带错误码的错误按窗口的当前语言显示
+func showError(err error, w fyne.Window, localizer *i18n.Localizer) {
+	dialog.ShowError(localizeError(localizer, err), w)
+}
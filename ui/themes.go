@@ -0,0 +1,326 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"image/color"
+	"io"
+	"passwdgen/gen"
+	"passwdgen/i18n"
+	pm "passwdgen/theme"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FontScales 可选的界面字号缩放比例
+var FontScales = []string{"80%", "90%", "100%", "110%", "125%", "150%"}
+
+// DefaultFontScale 默认的界面字号缩放比例
+const DefaultFontScale = "100%"
+
+// previewColorNames 预览中显示的主题颜色
+var previewColorNames = []fyne.ThemeColorName{
+	theme.ColorNameBackground,
+	theme.ColorNameForeground,
+	theme.ColorNamePrimary,
+	theme.ColorNameButton,
+	theme.ColorNameInputBackground,
+}
+
+// themePicker 主题选择,选中后立即应用并显示配色预览。
+// 下拉框显示主题的显示名称,设置中保存主题名称
+type themePicker struct {
+	reg         *pm.Registry
+	themeSelect *widget.Select
+	swatches    []*canvas.Rectangle
+	preview     fyne.CanvasObject
+	buttons     fyne.CanvasObject
+	mu          sync.Mutex
+	// 内置主题按界面语言翻译的显示名称,其余主题显示主题名称
+	labels map[string]string
+	// 选中主题的名称,切换语言后按名称重新显示
+	selected string
+	// 界面字体和字号
+	fontLabel       *widget.Label
+	fontButtons     fyne.CanvasObject
+	fontScaleSelect *widget.Select
+	// 用户选择的界面字体,nil时使用内置字体
+	userFont    fyne.Resource
+	builtinFont string
+}
+
+func newThemePicker(w fyne.Window, localizer *i18n.Localizer) *themePicker {
+	app := fyne.CurrentApp()
+	reg := pm.Default()
+	tp := &themePicker{reg: reg, labels: make(map[string]string)}
+	for i := 0; i < len(previewColorNames)+len(gen.Strengths)-1; i++ {
+		swatch := canvas.NewRectangle(color.Transparent)
+		swatch.SetMinSize(fyne.NewSize(24, 24))
+		tp.swatches = append(tp.swatches, swatch)
+	}
+	objects := make([]fyne.CanvasObject, 0, len(tp.swatches))
+	for _, swatch := range tp.swatches {
+		objects = append(objects, swatch)
+	}
+	tp.preview = container.NewHBox(objects...)
+	tp.themeSelect = widget.NewSelect(nil, func(label string) {
+		name := tp.themeName(label)
+		tp.mu.Lock()
+		tp.selected = name
+		tp.mu.Unlock()
+		app.Preferences().SetString(prefThemeKey, name)
+		tp.applyTheme()
+	})
+	tp.refreshOptions()
+	// 切换语言时更新内置主题的显示名称
+	for _, name := range reg.Names() {
+		if messageId, ok := i18n.ThemeNameMessageId(name); ok {
+			name := name
+			localizer.Register(messageId, func(value string) {
+				tp.mu.Lock()
+				tp.labels[name] = value
+				tp.mu.Unlock()
+				tp.refreshOptions()
+			})
+		}
+	}
+	importButton := newOptionButtonWidget(localizer, "", i18n.ThemeImportButtonLabelKey, theme.FolderOpenIcon(), func() {
+		fileOpen := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+			dir, err := pm.DefaultDir()
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			p, err := reg.Import(reader.URI().Name(), reader, dir)
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			tp.refreshOptions()
+			tp.selectTheme(p.Name)
+		}, w)
+		fileOpen.SetFilter(storage.NewExtensionFileFilter([]string{pm.FileExtension}))
+		fileOpen.Show()
+	})
+	exportButton := newOptionButtonWidget(localizer, "", i18n.ThemeExportButtonLabelKey, theme.DownloadIcon(), func() {
+		p, err := reg.Get(tp.selectedTheme())
+		if err != nil {
+			showError(err, w, localizer)
+			return
+		}
+		fileSave := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			if writer == nil {
+				return
+			}
+			defer writer.Close()
+			if err := pm.Encode(writer, p, app.Settings().ThemeVariant()); err != nil {
+				showError(err, w, localizer)
+			}
+		}, w)
+		fileSave.SetFileName(p.Id + pm.FileExtension)
+		fileSave.Show()
+	})
+	tp.buttons = container.New(layout.NewGridLayout(2), importButton, exportButton)
+	tp.initFont(w, localizer)
+	// 跟随系统时亮暗设置变化后更新预览
+	settingsChange := make(chan fyne.Settings)
+	app.Settings().AddChangeListener(settingsChange)
+	go func() {
+		for appSettings := range settingsChange {
+			if st, ok := appSettings.Theme().(*pm.SelectableTheme); ok {
+				tp.showPreview(st)
+			}
+		}
+	}()
+	return tp
+}
+
+// label 返回主题的显示名称
+func (tp *themePicker) label(name string) string {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	if label, ok := tp.labels[name]; ok {
+		return label
+	}
+	return name
+}
+
+// themeName 返回显示名称对应的主题名称
+func (tp *themePicker) themeName(label string) string {
+	for _, name := range tp.reg.Names() {
+		if tp.label(name) == label {
+			return name
+		}
+	}
+	return label
+}
+
+// selectedTheme 返回选中主题的名称,未选择时为空
+func (tp *themePicker) selectedTheme() string {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+	return tp.selected
+}
+
+// selectTheme 按主题名称选择并应用主题
+func (tp *themePicker) selectTheme(name string) {
+	tp.themeSelect.SetSelected(tp.label(name))
+}
+
+// selectSaved 选择设置中保存的主题,旧版本保存的内置主题名称转换为当前名称,没有保存或主题不存在时使用默认主题
+func (tp *themePicker) selectSaved() {
+	prefs := fyne.CurrentApp().Preferences()
+	if name := prefs.String(prefThemeKey); name != "" {
+		prefs.SetString(prefThemeKey, pm.CanonicalName(name))
+	}
+	tp.selectTheme(loadOption(prefThemeKey, tp.reg.Names(), DefaultTheme))
+}
+
+// refreshOptions 按当前的显示名称更新下拉框,保持选中的主题但不重新应用
+func (tp *themePicker) refreshOptions() {
+	selected := tp.selectedTheme()
+	names := tp.reg.Names()
+	options := make([]string, 0, len(names))
+	for _, name := range names {
+		options = append(options, tp.label(name))
+	}
+	tp.themeSelect.Options = options
+	if selected != "" {
+		tp.themeSelect.Selected = tp.label(selected)
+	}
+	tp.themeSelect.Refresh()
+}
+
+// initFont 创建界面字体和字号的选择控件,读取保存的字体文件,读取失败时使用内置字体
+func (tp *themePicker) initFont(w fyne.Window, localizer *i18n.Localizer) {
+	prefs := fyne.CurrentApp().Preferences()
+	tp.fontLabel = widget.NewLabel("")
+	localizer.Register(i18n.FontBuiltinLabelKey, func(value string) {
+		tp.builtinFont = value
+		if tp.userFont == nil {
+			tp.fontLabel.SetText(value)
+		}
+	})
+	if path := prefs.String(prefFontPathKey); path != "" {
+		if font, err := pm.LoadFont(path); err != nil {
+			showError(err, w, localizer)
+		} else {
+			tp.setUserFont(font)
+		}
+	}
+	chooseButton := newOptionButtonWidget(localizer, "", i18n.FontChooseButtonLabelKey, theme.FolderOpenIcon(), func() {
+		fileOpen := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			if reader == nil {
+				return
+			}
+			defer reader.Close()
+			content, err := io.ReadAll(reader)
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			font, err := pm.ParseFont(reader.URI().Name(), content)
+			if err != nil {
+				showError(err, w, localizer)
+				return
+			}
+			prefs.SetString(prefFontPathKey, reader.URI().Path())
+			tp.setUserFont(font)
+			tp.applyTheme()
+		}, w)
+		fileOpen.SetFilter(storage.NewExtensionFileFilter([]string{".ttf"}))
+		fileOpen.Show()
+	})
+	resetButton := newOptionButtonWidget(localizer, "", i18n.FontResetButtonLabelKey, theme.ContentUndoIcon(), func() {
+		prefs.RemoveValue(prefFontPathKey)
+		tp.setUserFont(nil)
+		tp.applyTheme()
+	})
+	tp.fontButtons = container.New(layout.NewGridLayout(2), chooseButton, resetButton)
+	tp.fontScaleSelect = widget.NewSelect(FontScales, func(value string) {
+		prefs.SetString(prefFontScaleKey, value)
+		tp.applyTheme()
+	})
+	tp.fontScaleSelect.SetSelected(loadOption(prefFontScaleKey, FontScales, DefaultFontScale))
+}
+
+// setUserFont 设置界面字体,nil表示使用内置字体
+func (tp *themePicker) setUserFont(font fyne.Resource) {
+	tp.userFont = font
+	if font == nil {
+		tp.fontLabel.SetText(tp.builtinFont)
+	} else {
+		tp.fontLabel.SetText(font.Name())
+	}
+}
+
+// resetFont 恢复内置字体和默认字号
+func (tp *themePicker) resetFont() {
+	tp.setUserFont(nil)
+	tp.fontScaleSelect.SetSelected(DefaultFontScale)
+	tp.applyTheme()
+}
+
+// applyTheme 按选择的主题、字体和字号应用主题,未选择主题时不应用
+func (tp *themePicker) applyTheme() {
+	if tp.themeSelect == nil || tp.selectedTheme() == "" {
+		return
+	}
+	st := pm.NewSelectableTheme(tp.selectedTheme())
+	st.UserFont = tp.userFont
+	st.FontScale = parseFontScale(tp.fontScaleSelect.Selected)
+	fyne.CurrentApp().Settings().SetTheme(st)
+	tp.showPreview(st)
+}
+
+// parseFontScale 解析字号缩放比例选项,例如 125% 返回 1.25,无法解析时返回0
+func parseFontScale(option string) float32 {
+	percent, err := strconv.Atoi(strings.TrimSuffix(option, "%"))
+	if err != nil {
+		return 0
+	}
+	return float32(percent) / 100
+}
+
+// showPreview 显示主题颜色和强度等级颜色
+func (tp *themePicker) showPreview(st *pm.SelectableTheme) {
+	variant := fyne.CurrentApp().Settings().ThemeVariant()
+	colors := make([]color.Color, 0, len(tp.swatches))
+	for _, colorName := range previewColorNames {
+		colors = append(colors, st.Color(colorName, variant))
+	}
+	for _, strength := range gen.Strengths {
+		if strength != gen.StrengthUnknown {
+			colors = append(colors, pm.StrengthColor(st, variant, strength))
+		}
+	}
+	for i, swatch := range tp.swatches {
+		swatch.FillColor = colors[i]
+		swatch.StrokeColor = st.Color(theme.ColorNameSeparator, variant)
+		swatch.StrokeWidth = 1
+		swatch.Refresh()
+	}
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"passwdgen/gen"
+	"passwdgen/i18n"
+	"passwdgen/qr"
+)
+
+// initWifiTabContent Wi-Fi凭据生成,口令遵循WPA的长度和字符规则,并显示可扫描的二维码
+func initWifiTabContent(w fyne.Window, localizer *i18n.Localizer, settings *settings) fyne.CanvasObject {
+	ssidEntry := widget.NewEntry()
+	hiddenCheck := newCheckWidget(localizer, "", i18n.WifiHiddenCheckLabelKey, nil, false)
+	lengthInfo := widget.NewLabel(fmt.Sprint(gen.WifiDefaultLength))
+	lengthSlide := widget.NewSlider(float64(gen.WifiMinLength), float64(gen.WifiMaxLength))
+	lengthSlide.Step = 1
+	lengthSlide.SetValue(float64(gen.WifiDefaultLength))
+	lengthSlide.OnChanged = func(value float64) {
+		lengthInfo.SetText(fmt.Sprintf("%0.0f", value))
+	}
+	passphraseEntry := widget.NewPasswordEntry()
+	passphraseEntry.Password = getBoolBindingValue(settings.alwaysMask)
+	qrImage := newQRImage()
+	var payload string
+	generateButton := newOptionButtonWidget(localizer, "", i18n.GenerateButtonLabelKey, theme.NavigateNextIcon(), func() {
+		// 使用密码生成页的当前字符集配置,长度由WPA规则限定
+		conf := loadPasswdGenConf(prefCurrentConfPrefix)
+		conf.Length = uint8(lengthSlide.Value)
+		result, err := gen.GenerateWifiCredential(&gen.WifiConf{SSID: ssidEntry.Text, Hidden: hiddenCheck.Checked,
+			Conf: conf})
+		if err != nil {
+			showError(err, w, localizer)
+			return
+		}
+		payload = result.Payload
+		passphraseEntry.SetText(result.Password)
+		if err := setQRImage(qrImage, payload); err != nil {
+			showError(err, w, localizer)
+		}
+	})
+	clearCredential := func() {
+		payload = ""
+		passphraseEntry.SetText("")
+		_ = setQRImage(qrImage, "")
+	}
+	// 名称变动后口令和二维码失效
+	ssidEntry.OnChanged = func(string) {
+		clearCredential()
+	}
+	hiddenCheck.OnChanged = func(bool) {
+		clearCredential()
+	}
+	copyButton := newOptionButtonWidget(localizer, "", i18n.CopyButtonLabelKey, theme.ContentCopyIcon(), func() {
+		if passphraseEntry.Text != "" {
+			copyToClipboard(w, passphraseEntry.Text)
+		}
+	})
+	exportPNGButton := newOptionButtonWidget(localizer, "", i18n.QRExportPNGButtonLabelKey, theme.DownloadIcon(), func() {
+		exportQR(w, localizer, payload, qr.FormatPNG)
+	})
+	exportSVGButton := newOptionButtonWidget(localizer, "", i18n.QRExportSVGButtonLabelKey, theme.DownloadIcon(), func() {
+		exportQR(w, localizer, payload, qr.FormatSVG)
+	})
+	settings.addFactoryResetListener(func() {
+		ssidEntry.SetText("")
+		hiddenCheck.SetChecked(false)
+		lengthSlide.SetValue(float64(gen.WifiDefaultLength))
+	})
+	form := container.New(layout.NewFormLayout(),
+		newLabelWidget(localizer, "", i18n.WifiSSIDFormLabelKey), container.NewBorder(nil, nil, nil, hiddenCheck, ssidEntry),
+		newLabelWidget(localizer, "", i18n.PasswdLengthSlideLabelKey), container.NewBorder(nil, nil, nil, lengthInfo, lengthSlide),
+		newLabelWidget(localizer, "", i18n.WifiPassphraseFormLabelKey), passphraseEntry,
+	)
+	wifiBox := container.NewVBox(
+		form,
+		container.New(layout.NewGridLayout(4), generateButton, copyButton, exportPNGButton, exportSVGButton),
+		container.NewCenter(qrImage),
+	)
+	wifiCard := widget.NewCard("", "", wifiBox)
+	localizer.Register(i18n.WifiCardTitleKey, func(value string) {
+		wifiCard.Title = value
+	})
+	return container.NewBorder(wifiCard, nil, nil, nil)
+}
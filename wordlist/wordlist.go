@@ -0,0 +1,314 @@
+package wordlist
+
+import (
+	"bufio"
+	"embed"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"passwdgen/errcode"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	English = "en"
+	Pinyin  = "pinyin"
+
+	AppDirName = "passwdgen"
+	// DirName 用户词表目录,位于配置目录下,每个 <名称>.txt 文件为一个词表
+	DirName       = "wordlists"
+	FileExtension = ".txt"
+	// MinWords 词表的最少单词数,保证每个单词至少10bit的熵
+	MinWords = 1024
+	// MaxWordLength 单词的最大字符数
+	MaxWordLength = 24
+)
+
+// Source 词表来源
+type Source string
+
+const (
+	SourceEmbedded Source = "embedded"
+	SourceUser     Source = "user"
+)
+
+//go:embed lists/*.txt
+var embeddedFS embed.FS
+
+// embeddedNames 内置词表,按显示顺序
+var embeddedNames = []string{English, Pinyin}
+
+// languageLists 界面语言对应的默认词表,其余语言使用英文词表
+var languageLists = map[string]string{
+	"zh": Pinyin,
+}
+
+var nameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// ErrorListNotFound 词表不存在
+const ErrorListNotFound errcode.Code = "word_list_not_found"
+
+// 登记错误码对应的提示消息
+func init() {
+	errcode.Register(ErrorListNotFound, "ErrorWordListNotFoundMessage")
+}
+
+var ListNotFoundError = errcode.New(ErrorListNotFound)
+
+// List 校验通过的词表
+type List struct {
+	Name   string
+	Source Source
+	// 用户词表的文件路径
+	Path  string
+	Words []string
+}
+
+// Entropy 每个单词的熵(bit)
+func (l *List) Entropy() float64 {
+	return math.Log2(float64(len(l.Words)))
+}
+
+// InvalidListError 词表校验错误,包含具体原因及所在行
+type InvalidListError struct {
+	Name string
+	Path string
+	// 出错的行号,0表示整个词表
+	Line   int
+	Reason string
+}
+
+func (e *InvalidListError) Error() string {
+	location := e.Name
+	if e.Path != "" {
+		location = e.Path
+	}
+	if e.Line > 0 {
+		return fmt.Sprintf("word list %s line %d: %s", location, e.Line, e.Reason)
+	}
+	return fmt.Sprintf("word list %s: %s", location, e.Reason)
+}
+
+// LoadError 加载用户词表时的所有错误,出错的词表不会被注册
+type LoadError struct {
+	Errors []error
+}
+
+func (le *LoadError) Error() string {
+	lines := make([]string, 0, len(le.Errors)+1)
+	lines = append(lines, fmt.Sprintf("invalid word lists (%d problem(s)):", len(le.Errors)))
+	for _, err := range le.Errors {
+		lines = append(lines, "  "+err.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Parse 读取并校验词表,每行一个单词,忽略空行和#开头的注释行。
+// 重复(不区分大小写)、过长或包含非法字符的单词以及单词数不足都会导致整个词表被拒绝,避免熵被悄悄降低
+func Parse(name string, r io.Reader) (*List, error) {
+	if !nameRegexp.MatchString(name) {
+		return nil, &InvalidListError{Name: name, Reason: "name must consist of lowercase letters, digits, '-' or '_'"}
+	}
+	list := &List{Name: name}
+	seen := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		if reason := checkWord(word); reason != "" {
+			return nil, &InvalidListError{Name: name, Line: line, Reason: reason}
+		}
+		key := strings.ToLower(word)
+		if first, ok := seen[key]; ok {
+			return nil, &InvalidListError{Name: name, Line: line,
+				Reason: fmt.Sprintf("duplicate word %q (first seen on line %d)", word, first)}
+		}
+		seen[key] = line
+		list.Words = append(list.Words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, &InvalidListError{Name: name, Line: line + 1, Reason: err.Error()}
+	}
+	if len(list.Words) < MinWords {
+		return nil, &InvalidListError{Name: name,
+			Reason: fmt.Sprintf("too few words: %d, at least %d required", len(list.Words), MinWords)}
+	}
+	return list, nil
+}
+
+// checkWord 校验单词,只允许字母、数字、连字符和撇号,返回不合法的原因
+func checkWord(word string) string {
+	if !utf8.ValidString(word) {
+		return "invalid UTF-8"
+	}
+	if n := utf8.RuneCountInString(word); n > MaxWordLength {
+		return fmt.Sprintf("word %q too long: %d characters, at most %d allowed", word, n, MaxWordLength)
+	}
+	for _, r := range word {
+		if unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r) || r == '-' || r == '\'' {
+			continue
+		}
+		return fmt.Sprintf("word %q contains invalid character %q", word, r)
+	}
+	return ""
+}
+
+// Registry 词表注册表,包含内置词表和用户目录中的词表
+type Registry struct {
+	mu    sync.RWMutex
+	lists map[string]*List
+	names []string
+	// 用户词表的加载错误
+	err error
+}
+
+// NewRegistry 返回只包含内置词表的注册表
+func NewRegistry() *Registry {
+	reg := &Registry{lists: make(map[string]*List)}
+	for _, name := range embeddedNames {
+		f, err := embeddedFS.Open("lists/" + name + FileExtension)
+		if err != nil {
+			panic(err)
+		}
+		list, err := Parse(name, f)
+		_ = f.Close()
+		if err != nil {
+			panic(err)
+		}
+		list.Source = SourceEmbedded
+		reg.add(list)
+	}
+	return reg
+}
+
+// DefaultDir 返回用户词表目录
+func DefaultDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, AppDirName, DirName), nil
+}
+
+// LoadDir 加载目录中的 *.txt 词表,目录不存在时忽略。
+// 校验失败或与已有词表重名的词表不会被注册,所有错误合并为 *LoadError 返回
+func (reg *Registry) LoadDir(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+FileExtension))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+	var errs []error
+	for _, path := range paths {
+		list, err := ParseFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, err := reg.Get(list.Name); err == nil {
+			errs = append(errs, &InvalidListError{Name: list.Name, Path: path,
+				Reason: fmt.Sprintf("name %q is already registered", list.Name)})
+			continue
+		}
+		reg.add(list)
+	}
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.err = nil
+	if len(errs) > 0 {
+		reg.err = &LoadError{Errors: errs}
+	}
+	return reg.err
+}
+
+// ParseFile 读取并校验用户词表文件,文件名(去掉扩展名)即词表名称
+func ParseFile(path string) (*List, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	list, err := Parse(strings.TrimSuffix(filepath.Base(path), FileExtension), f)
+	if err != nil {
+		var ile *InvalidListError
+		if errors.As(err, &ile) {
+			ile.Path = path
+		}
+		return nil, err
+	}
+	list.Source = SourceUser
+	list.Path = path
+	return list, nil
+}
+
+func (reg *Registry) add(list *List) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.lists[list.Name] = list
+	reg.names = append(reg.names, list.Name)
+}
+
+// Get 按名称返回词表
+func (reg *Registry) Get(name string) (*List, error) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	list, ok := reg.lists[name]
+	if !ok {
+		return nil, ListNotFoundError
+	}
+	return list, nil
+}
+
+// Names 返回所有词表名称,内置词表在前,用户词表按名称排序
+func (reg *Registry) Names() []string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	names := make([]string, len(reg.names))
+	copy(names, reg.names)
+	return names
+}
+
+// Err 返回用户词表的加载错误
+func (reg *Registry) Err() error {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+	return reg.err
+}
+
+var defaultRegistry *Registry
+var defaultOnce sync.Once
+
+// Default 返回内置词表和默认用户目录词表组成的注册表,用户词表的错误通过 Err 获取
+func Default() *Registry {
+	defaultOnce.Do(func() {
+		defaultRegistry = NewRegistry()
+		if dir, err := DefaultDir(); err == nil {
+			_ = defaultRegistry.LoadDir(dir)
+		}
+	})
+	return defaultRegistry
+}
+
+// ForLanguage 返回语言(如 zh_CN、en-US)默认使用的词表
+func ForLanguage(lang string) string {
+	base := strings.ToLower(lang)
+	if i := strings.IndexAny(base, "_-("); i >= 0 {
+		base = base[:i]
+	}
+	if name, ok := languageLists[base]; ok {
+		return name
+	}
+	return English
+}
@@ -0,0 +1,160 @@
+package wordlist
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fillerWords 返回 n 个不重复的ASCII单词
+func fillerWords(n int) []string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = fmt.Sprintf("filler%04d", i)
+	}
+	return words
+}
+
+// listText 把 lines 放在词表开头,其后补足 filler 个单词
+func listText(filler int, lines ...string) string {
+	return strings.Join(append(lines, fillerWords(filler)...), "\n")
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		listName string
+		text     string
+		// 期望的单词数,出错时忽略
+		words int
+		// 期望错误原因包含的文本,为空表示校验通过
+		reason string
+		line   int
+	}{
+		{name: "minimum size", text: listText(MinWords), words: MinWords},
+		{name: "too few", text: listText(MinWords - 1), reason: "too few words: 1023"},
+		{name: "comments and blanks not counted", text: listText(MinWords-1, "# header", "", "   ", "\t# indented"),
+			reason: "too few words: 1023"},
+		{name: "surrounding space trimmed", text: listText(MinWords-1, "  padded\t"), words: MinWords},
+		{name: "hash inside word", text: listText(MinWords-1, "c#"), reason: `invalid character '#'`, line: 1},
+		{name: "duplicate", text: listText(MinWords, "apple", "pear", "apple"),
+			reason: `duplicate word "apple" (first seen on line 1)`, line: 3},
+		{name: "duplicate ignores case", text: listText(MinWords, "Apple", "apple"),
+			reason: `duplicate word "apple" (first seen on line 1)`, line: 2},
+		{name: "duplicate after comment", text: listText(MinWords, "# fruit", "apple", "", "APPLE"),
+			reason: `duplicate word "APPLE" (first seen on line 2)`, line: 4},
+		{name: "non-ascii duplicate ignores case", text: listText(MinWords, "éclair", "Éclair"),
+			reason: `duplicate word "Éclair" (first seen on line 1)`, line: 2},
+		{name: "non-ascii words", text: listText(MinWords-6, "café", "naïve", "straße", "пароль", "词语", "éte"),
+			words: MinWords},
+		{name: "apostrophe and hyphen", text: listText(MinWords-2, "o'clock", "well-known"), words: MinWords},
+		{name: "max length counts characters", text: listText(MinWords-1, strings.Repeat("é", MaxWordLength)),
+			words: MinWords},
+		{name: "too long", text: listText(MinWords, strings.Repeat("a", MaxWordLength+1)), reason: "too long: 25", line: 1},
+		{name: "emoji", text: listText(MinWords, "smile😀"), reason: "invalid character '😀'", line: 1},
+		{name: "inner space", text: listText(MinWords, "two words"), reason: "invalid character ' '", line: 1},
+		{name: "invalid utf-8", text: listText(MinWords, "ok", "bad\xff"), reason: "invalid UTF-8", line: 2},
+		{name: "invalid name", listName: "My List", text: listText(MinWords), reason: "name must consist of"},
+		{name: "name prefix", listName: "-list", text: listText(MinWords), reason: "name must consist of"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := tt.listName
+			if name == "" {
+				name = "test"
+			}
+			list, err := Parse(name, strings.NewReader(tt.text))
+			if tt.reason == "" {
+				if err != nil {
+					t.Fatalf("Parse() error = %v", err)
+				}
+				if len(list.Words) != tt.words {
+					t.Errorf("len(Words) = %d, want %d", len(list.Words), tt.words)
+				}
+				return
+			}
+			var ile *InvalidListError
+			if !errors.As(err, &ile) {
+				t.Fatalf("Parse() error = %v, want *InvalidListError", err)
+			}
+			if !strings.Contains(ile.Reason, tt.reason) {
+				t.Errorf("Reason = %q, want %q", ile.Reason, tt.reason)
+			}
+			if ile.Line != tt.line {
+				t.Errorf("Line = %d, want %d", ile.Line, tt.line)
+			}
+		})
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"tech" + FileExtension:  listText(MinWords, "kernel"),
+		"short" + FileExtension: listText(10),
+		// 与内置词表重名
+		English + FileExtension: listText(MinWords),
+		// 非词表文件
+		"notes.md": "not a word list",
+	}
+	for name, text := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	reg := NewRegistry()
+	err := reg.LoadDir(dir)
+	var le *LoadError
+	if !errors.As(err, &le) {
+		t.Fatalf("LoadDir() error = %v, want *LoadError", err)
+	}
+	if len(le.Errors) != 2 {
+		t.Fatalf("LoadDir() errors = %v", le.Errors)
+	}
+	for _, err := range le.Errors {
+		var ile *InvalidListError
+		if !errors.As(err, &ile) || ile.Path == "" {
+			t.Errorf("error %v has no path", err)
+		}
+	}
+	if reg.Err() != err {
+		t.Errorf("Err() = %v, want %v", reg.Err(), err)
+	}
+	list, err := reg.Get("tech")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Source != SourceUser || len(list.Words) != MinWords+1 {
+		t.Errorf("tech: Source = %q, %d words", list.Source, len(list.Words))
+	}
+	if list.Entropy() < 10 {
+		t.Errorf("Entropy() = %v, want at least 10", list.Entropy())
+	}
+	if _, err := reg.Get("short"); !errors.Is(err, ListNotFoundError) {
+		t.Errorf("Get(short) error = %v, want %v", err, ListNotFoundError)
+	}
+	en, err := reg.Get(English)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if en.Source != SourceEmbedded {
+		t.Errorf("user list replaced embedded %q", English)
+	}
+	want := []string{English, Pinyin, "tech"}
+	if got := reg.Names(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Names() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadDirMissing(t *testing.T) {
+	reg := NewRegistry()
+	if err := reg.LoadDir(filepath.Join(t.TempDir(), "missing")); err != nil {
+		t.Fatalf("LoadDir() error = %v", err)
+	}
+	if reg.Err() != nil {
+		t.Errorf("Err() = %v", reg.Err())
+	}
+}